@@ -13,4 +13,7 @@ EOSQL
 
 psql -v ON_ERROR_STOP=1 --username "${POSTGRES_USER}" --dbname "${POSTGRES_APP_DB}" <<-EOSQL
   GRANT ALL ON SCHEMA public TO ${POSTGRES_APP_USER};
+
+  CREATE EXTENSION IF NOT EXISTS pg_trgm;
+  CREATE EXTENSION IF NOT EXISTS unaccent;
 EOSQL
\ No newline at end of file
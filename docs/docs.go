@@ -15,43 +15,106 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/list-songs": {
-            "post": {
-                "description": "list songs based on filter and page",
-                "consumes": [
-                    "application/json"
-                ],
+        "/admin/song/{song_id}": {
+            "delete": {
+                "description": "Removes song with its lyrics, revisions and relations right away, whether it's in trash or not. Requires admin token",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "trash"
                 ],
-                "summary": "List songs",
+                "summary": "Delete song permanently",
                 "parameters": [
                     {
-                        "description": "List songs request",
-                        "name": "message",
-                        "in": "body",
-                        "required": true,
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/domain.ListSongsRequest"
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
                         }
                     }
+                }
+            }
+        },
+        "/albums": {
+            "get": {
+                "description": "Lists albums newest added first, next page is fetched with next_cursor of previous response",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "List albums",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Only albums of artist",
+                        "name": "artist_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of requested page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of albums on page",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/model.Song"
-                            }
+                            "$ref": "#/definitions/domain.AlbumPage"
                         }
                     },
-                    "204": {
-                        "description": "No Content"
-                    },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
@@ -65,11 +128,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/song": {
+            },
             "post": {
-                "description": "Add new song to depository",
+                "description": "Adds album of existing artist, tracks are set separately",
                 "consumes": [
                     "application/json"
                 ],
@@ -77,23 +138,26 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Add song",
+                "summary": "Add album",
                 "parameters": [
                     {
-                        "description": "Add new song request",
+                        "description": "Add album request",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.AddSongRequest"
+                            "$ref": "#/definitions/domain.AlbumRequest"
                         }
                     }
                 ],
                 "responses": {
                     "201": {
-                        "description": "Created"
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Album"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -101,14 +165,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "422": {
+                        "description": "Unprocessable Entity",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
-                    "503": {
-                        "description": "Service Unavailable",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/ErrorResponse"
                         }
@@ -116,33 +180,31 @@ const docTemplate = `{
                 }
             }
         },
-        "/song-text/": {
+        "/albums/{album_id}": {
             "get": {
-                "description": "Gets specific verse of requested song's text",
+                "description": "Gets album with its track listing",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Get song text",
+                "summary": "Get album",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
-                        "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Number of verse to return (starting at 0)",
-                        "name": "verse",
-                        "in": "query"
+                        "description": "Album ID",
+                        "name": "album_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Album"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -163,11 +225,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/song/{song_id}": {
+            },
             "put": {
-                "description": "Edit any song info",
+                "description": "Replaces album fields, track listing is kept",
                 "consumes": [
                     "application/json"
                 ],
@@ -175,30 +235,33 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Edit song info",
+                "summary": "Modify album",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
-                        "name": "song_id",
+                        "description": "Album ID",
+                        "name": "album_id",
                         "in": "path",
                         "required": true
                     },
                     {
-                        "description": "Edit song request",
+                        "description": "Modify album request",
                         "name": "message",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/domain.UpdateSongRequest"
+                            "$ref": "#/definitions/domain.AlbumRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "OK"
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Album"
+                        }
                     },
                     "400": {
                         "description": "Bad Request",
@@ -212,6 +275,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/ErrorResponse"
                         }
                     },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -221,19 +290,19 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "Deletes song with song_id from depository",
+                "description": "Deletes album, its songs are kept",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "songs"
+                    "albums"
                 ],
-                "summary": "Delete song",
+                "summary": "Delete album",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Song ID",
-                        "name": "song_id",
+                        "description": "Album ID",
+                        "name": "album_id",
                         "in": "path",
                         "required": true
                     }
@@ -262,111 +331,3762 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "ErrorResponse": {
-            "type": "object",
-            "properties": {
-                "error": {
-                    "type": "string",
-                    "example": "Fatal error"
-                }
-            }
         },
-        "domain.AddSongRequest": {
-            "type": "object",
-            "required": [
-                "group",
-                "song"
-            ],
-            "properties": {
-                "group": {
-                    "type": "string",
-                    "minLength": 1
-                },
-                "song": {
-                    "type": "string",
-                    "minLength": 1
+        "/albums/{album_id}/tracks": {
+            "put": {
+                "description": "Replaces track listing of album, tracks are numbered in given order on each disc. Used to add, remove and reorder tracks",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "albums"
+                ],
+                "summary": "Set album tracks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Album ID",
+                        "name": "album_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Album tracks request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.SetTracksRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Album"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "domain.ListSongsRequest": {
-            "type": "object",
-            "properties": {
-                "filter": {
-                    "$ref": "#/definitions/domain.SongFilter"
-                },
-                "page": {
-                    "type": "integer"
+        "/artists": {
+            "get": {
+                "description": "Lists artists ordered by slug, next page is fetched with next_cursor of previous response",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "List artists",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor of requested page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of artists on page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ArtistPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
                 }
             }
         },
-        "domain.SongFilter": {
-            "type": "object",
-            "properties": {
-                "group": {
-                    "type": "string"
-                },
-                "name": {
-                    "type": "string"
-                },
+        "/artists/{artist_id}": {
+            "get": {
+                "description": "Gets artist with aliases and number of songs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Get artist",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Artist ID or slug",
+                        "name": "artist_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Artist"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Changes canonical name and slug of artist, old name is kept as alias. Songs of artist are renamed too",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Rename artist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "artist_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rename artist request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.RenameArtistRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Deletes artist which has no songs and albums",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Delete artist",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "artist_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/artists/{artist_id}/aliases": {
+            "post": {
+                "description": "Adds aliases songs are resolved to artist by. Artists already known by any of aliases are merged into this one with their songs and aliases",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "artists"
+                ],
+                "summary": "Merge artist aliases",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Artist ID",
+                        "name": "artist_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge aliases request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.MergeAliasesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Artist"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres": {
+            "get": {
+                "description": "Lists genre vocabulary ordered by name with number of songs",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "List genres",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Genre"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Adds genre to vocabulary, its slug is made of name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Add genre",
+                "parameters": [
+                    {
+                        "description": "Add genre request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.AddGenreRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/model.Genre"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/genres/{genre_id}": {
+            "delete": {
+                "description": "Deletes genre which isn't set on any song",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "genres"
+                ],
+                "summary": "Delete genre",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Genre ID",
+                        "name": "genre_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/list-songs": {
+            "post": {
+                "description": "list songs based on filter, pages are fetched with next_cursor and prev_cursor of previous response. Songs are ordered by sort fields, search hits are ordered by relevance by default",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "List songs",
+                "parameters": [
+                    {
+                        "description": "List songs request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.ListSongsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SongPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song": {
+            "post": {
+                "description": "Add new song to depository, its details are looked up in background",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Add song",
+                "parameters": [
+                    {
+                        "description": "Add new song request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.AddSongRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author of change saved with song revision",
+                        "name": "X-Author",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of change saved with song revision",
+                        "name": "X-Change-Reason",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/domain.AddSongResponse"
+                        },
+                        "headers": {
+                            "Location": {
+                                "type": "string",
+                                "description": "Song enrichment status URL"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/DuplicateSongResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song-text/": {
+            "get": {
+                "description": "Gets sections of requested song's text: one by index, all of type or all with label. First section is returned by default",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song text",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Index of section to return (starting at 0)",
+                        "name": "verse",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Type of sections to return: verse, chorus, bridge, intro or outro",
+                        "name": "type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Label of sections to return, e.g. Chorus or Verse 2",
+                        "name": "label",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SongTextResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}": {
+            "get": {
+                "description": "Gets song with all its info or selected fields only, supports conditional requests with If-None-Match.\nMerged song redirects to song it was merged into",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated song fields to return, text_length and verse_count can be requested too",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of cached song",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Song entity tag, weak for selected fields"
+                            }
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Edit any song info",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Edit song info",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of edited song version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Edit song request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.UpdateSongRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author of change saved with song revision",
+                        "name": "X-Author",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of change saved with song revision",
+                        "name": "X-Change-Reason",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New song entity tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/DuplicateSongResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Moves song with song_id to trash, it can be restored until it's purged after retention period",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Delete song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of deleted song version",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Applies JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) to song, null or removed fields are cleared.\nCredits are matched to artists by name, primary credit follows group",
+                "consumes": [
+                    "application/merge-patch+json",
+                    "application/json-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Patch song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of patched song version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Patch document",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author of change saved with song revision",
+                        "name": "X-Author",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of change saved with song revision",
+                        "name": "X-Change-Reason",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New song entity tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/chords": {
+            "get": {
+                "description": "Renders chord sheet of song as plain text with chords above lyrics or as JSON.\nChords can be transposed, transposed chords are spelled by key unless accidentals are set",
+                "produces": [
+                    "text/plain",
+                    "application/json"
+                ],
+                "tags": [
+                    "chords"
+                ],
+                "summary": "Get chord sheet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "text",
+                            "json"
+                        ],
+                        "type": "string",
+                        "description": "Format, text by default",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Semitones to transpose by, from -11 to 11",
+                        "name": "transpose",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "sharp",
+                            "flat"
+                        ],
+                        "type": "string",
+                        "description": "Spelling of transposed chords",
+                        "name": "accidentals",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChordSheet"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces chord sheet of song with ChordPro document. Chords, {key} and {capo} are validated,\nsections are marked by {start_of_chorus}, {start_of_verse}, {start_of_bridge} and {start_of_tab}",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chords"
+                ],
+                "summary": "Upload chord sheet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "ChordPro document",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ChordSheet"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes chord sheet of song, lyrics are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "chords"
+                ],
+                "summary": "Delete chord sheet",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/lrc": {
+            "get": {
+                "description": "Formats synchronized lyrics of song as LRC, enhanced LRC keeps word timings",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "timed lyrics"
+                ],
+                "summary": "Download LRC lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Export enhanced LRC",
+                        "name": "enhanced",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "LRC document",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replaces synchronized lyrics of song with LRC or enhanced LRC document.\nTimestamps must not go backward, except repeats of line with several timestamps which are placed by their timestamps. [offset:] tag is applied to all of them",
+                "consumes": [
+                    "text/plain"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timed lyrics"
+                ],
+                "summary": "Upload LRC lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "LRC document",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TimedLine"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/lyrics": {
+            "get": {
+                "description": "Gets verses of song lyrics by verse range or page, or single lines by verse:line references.\nVerses and lines are numbered from 0. Out of range requests are answered with valid range",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "First verse of range",
+                        "name": "verse_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Last verse of range",
+                        "name": "verse_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page of verses, starting at 1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of verses on page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated line references, e.g. 0:1,2:0-3",
+                        "name": "lines",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.LyricsPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/LyricsRangeResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/merge": {
+            "post": {
+                "description": "Merges duplicate into song: albums, credits, genres, tags and revisions of duplicate are moved to song,\nsynchronized lyrics and chord sheet are moved when song has none. Duplicate is removed and its ID redirects to song",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "duplicates"
+                ],
+                "summary": "Merge duplicate song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "ID of kept song",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Merge song request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.MergeSongsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of kept song version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author of change saved with song revision",
+                        "name": "X-Author",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of change saved with song revision",
+                        "name": "X-Change-Reason",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New song entity tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/refresh": {
+            "post": {
+                "description": "Looks up song details again and reports changed fields, fields set by hand are kept unless forced",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Refresh song details",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Refresh options",
+                        "name": "message",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefreshSongRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author of change saved with song revision",
+                        "name": "X-Author",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of change saved with song revision",
+                        "name": "X-Change-Reason",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefreshResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        },
+                        "headers": {
+                            "Retry-After": {
+                                "type": "integer",
+                                "description": "Seconds until music info service is requested again"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/restore": {
+            "post": {
+                "description": "Takes song out of trash, song added again with same artist and name conflicts with it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "Restore deleted song",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Song entity tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/revisions": {
+            "get": {
+                "description": "Lists revisions of song newest first, next page is fetched with next_cursor of previous response",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "List song revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor of requested page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of revisions on page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RevisionPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/revisions/{revision}": {
+            "get": {
+                "description": "Gets song revision with song fields saved by it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Get song revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.SongRevision"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/revisions/{revision}/diff": {
+            "get": {
+                "description": "Compares song revision with another one, previous revision by default.\nLyrics are compared line by line as unified diff, other fields are listed with old and new values",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Diff song revisions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision to compare with, 0 for empty song",
+                        "name": "from",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RevisionDiff"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/revisions/{revision}/restore": {
+            "post": {
+                "description": "Sets song fields to ones saved by revision, restored song is saved as new revision.\nRestored lyrics, release date and link are marked as set by hand",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "revisions"
+                ],
+                "summary": "Restore song revision",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Revision number",
+                        "name": "revision",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of current song version",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author of change saved with song revision",
+                        "name": "X-Author",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of change saved with song revision",
+                        "name": "X-Change-Reason",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Song"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "New song entity tag"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/DuplicateSongResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/status": {
+            "get": {
+                "description": "Reports whether song details were looked up",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Get song enrichment status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.EnrichmentState"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/timed-lyrics": {
+            "get": {
+                "description": "Gets lines of synchronized lyrics with start offsets and word timings in milliseconds",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timed lyrics"
+                ],
+                "summary": "Get synchronized lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.TimedLine"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Removes synchronized lyrics of song, plain lyrics are kept",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timed lyrics"
+                ],
+                "summary": "Delete synchronized lyrics",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/song/{song_id}/timed-lyrics/active": {
+            "get": {
+                "description": "Gets lines of synchronized lyrics sung at playback position, word being sung and next line",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "timed lyrics"
+                ],
+                "summary": "Get active lyrics lines",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Song ID",
+                        "name": "song_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Playback position in milliseconds",
+                        "name": "position_ms",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.ActiveLyrics"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/duplicates": {
+            "get": {
+                "description": "Reports pairs of songs with similar names, scored by similarity of names, groups and lyrics. Most likely duplicates come first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "duplicates"
+                ],
+                "summary": "Find duplicate songs",
+                "parameters": [
+                    {
+                        "type": "number",
+                        "description": "Minimal name similarity, from 0 to 1",
+                        "name": "threshold",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of reported pairs",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.DuplicateReport"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/facets": {
+            "post": {
+                "description": "Counts songs matching filter per genre, tag, artist and release year",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Song facets",
+                "parameters": [
+                    {
+                        "description": "Song facets request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.SongFacetsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SongFacets"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/refresh": {
+            "post": {
+                "description": "Refreshes songs matching filter, failures are reported per song",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "songs"
+                ],
+                "summary": "Refresh details of many songs",
+                "parameters": [
+                    {
+                        "description": "Bulk refresh request",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefreshSongsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Author of change saved with song revision",
+                        "name": "X-Author",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Reason of change saved with song revision",
+                        "name": "X-Change-Reason",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.RefreshSongsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/songs/trash": {
+            "get": {
+                "description": "Lists songs in trash, they are purged after retention period. Next page is fetched with next_cursor of previous response",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "trash"
+                ],
+                "summary": "List deleted songs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Cursor of requested page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of songs on page",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/domain.SongPage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/status": {
+            "get": {
+                "description": "Reports state of external dependencies",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Service status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/StatusResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "DuplicateSongResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "song with same artist and name already exists, id 12"
+                },
+                "existing_id": {
+                    "description": "ID of existing song",
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Fatal error"
+                }
+            }
+        },
+        "LyricsRangeResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "verse is out of range, song has verses 0-5"
+                },
+                "first": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "last": {
+                    "type": "integer",
+                    "example": 5
+                },
+                "verse": {
+                    "description": "Verse lines were requested from",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "StatusResponse": {
+            "type": "object",
+            "properties": {
+                "music_info": {
+                    "$ref": "#/definitions/metadata.BreakerStatus"
+                }
+            }
+        },
+        "domain.ActiveLine": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "$ref": "#/definitions/model.TimedLine"
+                },
+                "word": {
+                    "description": "Index of word sung at position, set when line has word timings",
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "domain.ActiveLyrics": {
+            "type": "object",
+            "properties": {
+                "lines": {
+                    "description": "Lines starting at same time are active together, empty before first line, during breaks and after end of last one",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.ActiveLine"
+                    }
+                },
+                "next": {
+                    "description": "First line starting after position",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.TimedLine"
+                        }
+                    ]
+                },
+                "position_ms": {
+                    "type": "integer",
+                    "example": 13400
+                }
+            }
+        },
+        "domain.AddGenreRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "Shoegaze"
+                }
+            }
+        },
+        "domain.AddSongRequest": {
+            "type": "object",
+            "required": [
+                "group",
+                "song"
+            ],
+            "properties": {
+                "group": {
+                    "description": "Artist name or any of its aliases, unknown name adds new artist",
+                    "type": "string",
+                    "minLength": 1
+                },
+                "song": {
+                    "type": "string",
+                    "minLength": 1
+                }
+            }
+        },
+        "domain.AddSongResponse": {
+            "type": "object",
+            "properties": {
+                "enrichment_status": {
+                    "type": "string",
+                    "example": "pending"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status_url": {
+                    "type": "string",
+                    "example": "/song/1/status"
+                }
+            }
+        },
+        "domain.AlbumPage": {
+            "type": "object",
+            "properties": {
+                "albums": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Album"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "42"
+                }
+            }
+        },
+        "domain.AlbumRequest": {
+            "type": "object",
+            "required": [
+                "artist_id",
+                "title"
+            ],
+            "properties": {
+                "artist_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "cover_link": {
+                    "type": "string",
+                    "example": "https://example.com/cover.jpg"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-03T00:00:00Z"
+                },
+                "title": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "Black Holes and Revelations"
+                },
+                "type": {
+                    "description": "Release type: lp, ep, single or compilation, lp by default",
+                    "enum": [
+                        "lp",
+                        "ep",
+                        "single",
+                        "compilation"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AlbumType"
+                        }
+                    ],
+                    "example": "lp"
+                }
+            }
+        },
+        "domain.ArtistPage": {
+            "type": "object",
+            "properties": {
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Artist"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "the-beatles"
+                }
+            }
+        },
+        "domain.CreditRequest": {
+            "type": "object",
+            "required": [
+                "artist"
+            ],
+            "properties": {
+                "artist": {
+                    "description": "Artist name, resolved through aliases",
+                    "type": "string",
+                    "example": "Brian Eno"
+                },
+                "role": {
+                    "description": "Credit role: featuring, composer, lyricist or producer",
+                    "enum": [
+                        "featuring",
+                        "composer",
+                        "lyricist",
+                        "producer"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CreditRole"
+                        }
+                    ],
+                    "example": "producer"
+                }
+            }
+        },
+        "domain.DuplicateCandidate": {
+            "type": "object",
+            "properties": {
+                "artist_similarity": {
+                    "type": "number",
+                    "example": 1
+                },
+                "duplicate_group": {
+                    "type": "string",
+                    "example": "MUSE"
+                },
+                "duplicate_id": {
+                    "type": "integer",
+                    "example": 31
+                },
+                "duplicate_song": {
+                    "type": "string",
+                    "example": "Supermassive Black-Hole"
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "lyrics_similarity": {
+                    "description": "Trigram similarity of lyrics, null when any of songs has no lyrics",
+                    "type": "number",
+                    "example": 0.97
+                },
+                "name_similarity": {
+                    "description": "Trigram similarity of names and groups, from 0 to 1",
+                    "type": "number",
+                    "example": 0.83
+                },
+                "score": {
+                    "description": "Average of similarities",
+                    "type": "number",
+                    "example": 0.93
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 12
+                }
+            }
+        },
+        "domain.DuplicateReport": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.DuplicateCandidate"
+                    }
+                },
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "threshold": {
+                    "description": "Minimal name similarity of candidates",
+                    "type": "number",
+                    "example": 0.6
+                }
+            }
+        },
+        "domain.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 124
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Rock"
+                },
+                "value": {
+                    "description": "Genre slug, tag, artist ID or release year",
+                    "type": "string",
+                    "example": "rock"
+                }
+            }
+        },
+        "domain.FieldChange": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "text"
+                },
+                "new": {},
+                "old": {},
+                "protected": {
+                    "description": "Field was set by hand and is kept",
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.ListSongsRequest": {
+            "type": "object",
+            "properties": {
+                "cursor": {
+                    "description": "Cursor of requested page, first page is returned when empty",
+                    "type": "string",
+                    "example": "eyJzIjoiLWlkIiwidiI6WyI0MiJdfQ"
+                },
+                "fields": {
+                    "description": "Song fields to return, lyrics are replaced with text_length and verse_count when empty",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "song",
+                        "group",
+                        "release_date"
+                    ]
+                },
+                "filter": {
+                    "$ref": "#/definitions/domain.SongFilter"
+                },
+                "highlight": {
+                    "description": "Add highlighted lyrics snippet to search hits",
+                    "type": "boolean"
+                },
+                "limit": {
+                    "description": "Number of songs on page, configured default is used when zero",
+                    "type": "integer",
+                    "example": 10
+                },
+                "sort": {
+                    "description": "Sort fields: name, group, release_date, created_at or updated_at, \"-\" prefix sorts descending.\nSearch hits are ordered by relevance when empty, other songs by newest first",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "-release_date",
+                        "name"
+                    ]
+                },
+                "total": {
+                    "description": "Count all songs matching filter",
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.LyricsLine": {
+            "type": "object",
+            "properties": {
+                "line": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "text": {
+                    "type": "string",
+                    "example": "You set my soul alight"
+                },
+                "verse": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "domain.LyricsPage": {
+            "type": "object",
+            "properties": {
+                "has_more": {
+                    "description": "There are verses after selected ones",
+                    "type": "boolean"
+                },
+                "lines": {
+                    "description": "Lines requested by references",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.LyricsLine"
+                    }
+                },
+                "verse_count": {
+                    "description": "Number of verses in lyrics",
+                    "type": "integer",
+                    "example": 6
+                },
+                "verses": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Section"
+                    }
+                }
+            }
+        },
+        "domain.MergeAliasesRequest": {
+            "type": "object",
+            "required": [
+                "aliases"
+            ],
+            "properties": {
+                "aliases": {
+                    "description": "Names added to artist, artists known by them are merged into this one",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Beatles",
+                        "the beatles"
+                    ]
+                }
+            }
+        },
+        "domain.MergeSongsRequest": {
+            "type": "object",
+            "required": [
+                "duplicate_id"
+            ],
+            "properties": {
+                "duplicate_id": {
+                    "description": "ID of song merged into one in path, it's removed and redirects to kept song",
+                    "type": "integer",
+                    "example": 31
+                }
+            }
+        },
+        "domain.RefreshResult": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "Changes were saved",
+                    "type": "boolean"
+                },
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldChange"
+                    }
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "domain.RefreshSongRequest": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "Only report changes without saving them",
+                    "type": "boolean"
+                },
+                "force": {
+                    "description": "Overwrite fields set by hand",
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.RefreshSongsRequest": {
+            "type": "object",
+            "properties": {
+                "dry_run": {
+                    "description": "Only report changes without saving them",
+                    "type": "boolean"
+                },
+                "filter": {
+                    "$ref": "#/definitions/domain.SongFilter"
+                },
+                "force": {
+                    "description": "Overwrite fields set by hand",
+                    "type": "boolean"
+                }
+            }
+        },
+        "domain.RefreshSongsResponse": {
+            "type": "object",
+            "properties": {
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.RefreshResult"
+                    }
+                }
+            }
+        },
+        "domain.RenameArtistRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "minLength": 1,
+                    "example": "The Beatles"
+                }
+            }
+        },
+        "domain.RevisionDiff": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "description": "Changes of fields other than lyrics",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FieldChange"
+                    }
+                },
+                "diff": {
+                    "description": "Unified diff of lyrics lines, empty when lyrics are same",
+                    "type": "string",
+                    "example": "--- revision 2\n+++ revision 3\n@@ -1 +1 @@\n-old line\n+new line\n"
+                },
+                "from": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "to": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "domain.RevisionPage": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "next_cursor": {
+                    "description": "Cursor of next page, null on last page",
+                    "type": "string",
+                    "example": "12"
+                },
+                "revisions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SongRevision"
+                    }
+                }
+            }
+        },
+        "domain.SetTracksRequest": {
+            "type": "object",
+            "properties": {
+                "tracks": {
+                    "description": "Tracks in playing order, they are numbered from 1 on each disc",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.TrackRequest"
+                    }
+                }
+            }
+        },
+        "domain.SongFacets": {
+            "type": "object",
+            "properties": {
+                "artists": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FacetCount"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FacetCount"
+                    }
+                },
+                "release_years": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FacetCount"
+                    }
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.FacetCount"
+                    }
+                }
+            }
+        },
+        "domain.SongFacetsRequest": {
+            "type": "object",
+            "properties": {
+                "filter": {
+                    "$ref": "#/definitions/domain.SongFilter"
+                }
+            }
+        },
+        "domain.SongFilter": {
+            "type": "object",
+            "properties": {
+                "artist_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_after": {
+                    "description": "Songs added within range, ends are excluded",
+                    "type": "string",
+                    "example": "2024-10-01T00:00:00Z"
+                },
+                "created_before": {
+                    "type": "string",
+                    "example": "2024-11-01T00:00:00Z"
+                },
+                "credited_artist_id": {
+                    "description": "Songs crediting artist in any role",
+                    "type": "integer",
+                    "example": 2
+                },
+                "fuzzy_threshold": {
+                    "description": "Minimal similarity of fuzzy match, from 0 to 1",
+                    "type": "number",
+                    "example": 0.3
+                },
+                "genres": {
+                    "description": "Songs of any of genre slugs",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "rock",
+                        "pop"
+                    ]
+                },
+                "group": {
+                    "type": "string"
+                },
+                "match": {
+                    "description": "How name and group are matched, case and accent insensitive: contains, exact or fuzzy",
+                    "type": "string",
+                    "example": "fuzzy"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "q": {
+                    "description": "Full text search query, supports \"phrases\", prefix*, -negation and OR",
+                    "type": "string",
+                    "example": "\"soul alight\" -baby"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2024-10-29T15:04:05.000Z"
+                },
+                "release_date_from": {
+                    "description": "Release date range, both ends are included",
+                    "type": "string",
+                    "example": "2000-01-01T00:00:00Z"
+                },
+                "release_date_to": {
+                    "type": "string",
+                    "example": "2009-12-31T00:00:00Z"
+                },
+                "search_config": {
+                    "description": "Text search configuration: english, russian or simple",
+                    "type": "string",
+                    "example": "english"
+                },
+                "tags_all": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "space"
+                    ]
+                },
+                "tags_any": {
+                    "description": "Songs tagged with any, all or none of tags",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "live",
+                        "acoustic"
+                    ]
+                },
+                "tags_none": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "cover"
+                    ]
+                },
+                "text": {
+                    "type": "string"
+                },
+                "updated_since": {
+                    "description": "Songs changed at or after time",
+                    "type": "string",
+                    "example": "2024-10-29T15:04:05.000Z"
+                }
+            }
+        },
+        "domain.SongPage": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "next_cursor": {
+                    "type": "string",
+                    "example": "eyJzIjoiLWlkIiwidiI6WyIzMiJdfQ"
+                },
+                "prev_cursor": {
+                    "type": "string"
+                },
+                "songs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Song"
+                    }
+                },
+                "total": {
+                    "description": "Number of songs matching filter, only counted on request",
+                    "type": "integer",
+                    "example": 125
+                }
+            }
+        },
+        "domain.SongTextResponse": {
+            "type": "object",
+            "properties": {
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Section"
+                    }
+                },
+                "text": {
+                    "description": "Text of selected sections separated by blank line",
+                    "type": "string",
+                    "example": "Ooh\nYou set my soul alight"
+                }
+            }
+        },
+        "domain.TrackRequest": {
+            "type": "object",
+            "required": [
+                "song_id"
+            ],
+            "properties": {
+                "disc_number": {
+                    "description": "Disc of track, first disc when zero",
+                    "type": "integer",
+                    "example": 1
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "domain.UpdateSongRequest": {
+            "type": "object",
+            "properties": {
+                "credits": {
+                    "description": "Replace credits besides primary one, which follows group. Empty list removes them",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/domain.CreditRequest"
+                    }
+                },
+                "genres": {
+                    "description": "Replace genre slugs, empty list removes them",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "rock"
+                    ]
+                },
+                "group": {
+                    "type": "string"
+                },
+                "link": {
+                    "type": "string"
+                },
                 "release_date": {
                     "type": "string",
                     "example": "2024-10-29T15:04:05.000Z"
                 },
+                "song": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "Replace tags, empty list removes them",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "space",
+                        "falsetto"
+                    ]
+                },
                 "text": {
                     "type": "string"
                 }
             }
         },
-        "domain.UpdateSongRequest": {
+        "metadata.BreakerStatus": {
             "type": "object",
             "properties": {
-                "group": {
-                    "type": "string"
+                "failures": {
+                    "type": "integer",
+                    "example": 0
                 },
-                "link": {
+                "retry_after_seconds": {
+                    "type": "number",
+                    "example": 0
+                },
+                "state": {
+                    "type": "string",
+                    "example": "closed"
+                }
+            }
+        },
+        "model.Album": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "artist_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "cover_link": {
+                    "type": "string",
+                    "example": "https://example.com/cover.jpg"
+                },
+                "created_at": {
                     "type": "string"
                 },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
                 "release_date": {
                     "type": "string",
-                    "example": "2024-10-29T15:04:05.000Z"
+                    "example": "2006-07-03T00:00:00Z"
                 },
-                "song": {
+                "title": {
+                    "type": "string",
+                    "example": "Black Holes and Revelations"
+                },
+                "track_count": {
+                    "type": "integer",
+                    "example": 11
+                },
+                "tracks": {
+                    "description": "Track listing ordered by disc and track number, only filled for single album",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Track"
+                    }
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AlbumType"
+                        }
+                    ],
+                    "example": "lp"
+                },
+                "updated_at": {
                     "type": "string"
+                }
+            }
+        },
+        "model.AlbumType": {
+            "type": "string",
+            "enum": [
+                "lp",
+                "ep",
+                "single",
+                "compilation"
+            ],
+            "x-enum-varnames": [
+                "AlbumLP",
+                "AlbumEP",
+                "AlbumSingle",
+                "AlbumCompilation"
+            ]
+        },
+        "model.Artist": {
+            "type": "object",
+            "properties": {
+                "aliases": {
+                    "description": "Names songs are resolved to artist by, canonical name included",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "The Beatles",
+                        "Beatles"
+                    ]
                 },
-                "text": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "The Beatles"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "the-beatles"
+                },
+                "song_count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChordLine": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string",
+                    "example": "Slowly"
+                },
+                "segments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ChordSegment"
+                    }
+                }
+            }
+        },
+        "model.ChordSection": {
+            "type": "object",
+            "properties": {
+                "label": {
+                    "type": "string",
+                    "example": "Chorus"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ChordLine"
+                    }
+                },
+                "repeat": {
+                    "description": "Chorus is repeated here, its lines are those of last chorus",
+                    "type": "boolean"
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.ChordSectionType"
+                        }
+                    ],
+                    "example": "chorus"
+                }
+            }
+        },
+        "model.ChordSectionType": {
+            "type": "string",
+            "enum": [
+                "verse",
+                "chorus",
+                "bridge",
+                "tab"
+            ],
+            "x-enum-varnames": [
+                "ChordVerse",
+                "ChordChorus",
+                "ChordBridge",
+                "ChordTab"
+            ]
+        },
+        "model.ChordSegment": {
+            "type": "object",
+            "properties": {
+                "chord": {
+                    "type": "string",
+                    "example": "G/B"
+                },
+                "lyrics": {
+                    "type": "string",
+                    "example": "Amazing "
+                }
+            }
+        },
+        "model.ChordSheet": {
+            "type": "object",
+            "properties": {
+                "capo": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "key": {
+                    "type": "string",
+                    "example": "G"
+                },
+                "meta": {
+                    "description": "Other metadata directives such as artist, tempo or time",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "sections": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ChordSection"
+                    }
+                },
+                "subtitle": {
+                    "type": "string",
+                    "example": "Traditional"
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Amazing Grace"
+                }
+            }
+        },
+        "model.Credit": {
+            "type": "object",
+            "properties": {
+                "artist": {
+                    "type": "string",
+                    "example": "Brian Eno"
+                },
+                "artist_id": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "role": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.CreditRole"
+                        }
+                    ],
+                    "example": "producer"
+                }
+            }
+        },
+        "model.CreditRole": {
+            "type": "string",
+            "enum": [
+                "primary",
+                "featuring",
+                "composer",
+                "lyricist",
+                "producer"
+            ],
+            "x-enum-varnames": [
+                "CreditPrimary",
+                "CreditFeaturing",
+                "CreditComposer",
+                "CreditLyricist",
+                "CreditProducer"
+            ]
+        },
+        "model.EnrichmentState": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "enrichment_status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.EnrichmentStatus"
+                        }
+                    ],
+                    "example": "pending"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "next_attempt_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.EnrichmentStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "enriched",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "EnrichmentPending",
+                "EnrichmentEnriched",
+                "EnrichmentFailed"
+            ]
+        },
+        "model.Genre": {
+            "type": "object",
+            "properties": {
+                "created_at": {
                     "type": "string"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "Rock"
+                },
+                "slug": {
+                    "type": "string",
+                    "example": "rock"
+                },
+                "song_count": {
+                    "type": "integer",
+                    "example": 124
+                }
+            }
+        },
+        "model.Section": {
+            "type": "object",
+            "properties": {
+                "index": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Chorus"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "Glaciers melting in the dead of night"
+                    ]
+                },
+                "repeat_of": {
+                    "description": "Index of first section with same lines",
+                    "type": "integer",
+                    "example": 1
+                },
+                "type": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SectionType"
+                        }
+                    ],
+                    "example": "chorus"
                 }
             }
         },
+        "model.SectionType": {
+            "type": "string",
+            "enum": [
+                "verse",
+                "chorus",
+                "bridge",
+                "intro",
+                "outro"
+            ],
+            "x-enum-varnames": [
+                "SectionVerse",
+                "SectionChorus",
+                "SectionBridge",
+                "SectionIntro",
+                "SectionOutro"
+            ]
+        },
         "model.Song": {
             "type": "object",
             "properties": {
-                "createdAt": {
+                "artist_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Credit"
+                    }
+                },
+                "deleted_at": {
+                    "description": "Time song was moved to trash",
                     "type": "string"
                 },
+                "enrichment_status": {
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.EnrichmentStatus"
+                        }
+                    ],
+                    "example": "enriched"
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "rock",
+                        "alternative"
+                    ]
+                },
                 "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "headline": {
                     "type": "string"
                 },
                 "id": {
-                    "type": "integer"
+                    "type": "integer",
+                    "example": 1
                 },
                 "link": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "manual_fields": {
+                    "description": "Fields set by hand, they are kept on refresh unless forced",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "rank": {
+                    "description": "Search hit relevance and lyrics snippet",
+                    "type": "number"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16T00:00:00Z"
+                },
+                "score": {
+                    "description": "Name and group similarity in fuzzy match mode",
+                    "type": "number"
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "space",
+                        "falsetto"
+                    ]
+                },
+                "text": {
                     "type": "string"
                 },
-                "name": {
+                "text_length": {
+                    "description": "Lyrics size, selected instead of lyrics",
+                    "type": "integer",
+                    "example": 1830
+                },
+                "updated_at": {
                     "type": "string"
                 },
-                "releaseDate": {
+                "verse_count": {
+                    "type": "integer",
+                    "example": 6
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "model.SongRevision": {
+            "type": "object",
+            "properties": {
+                "author": {
+                    "type": "string",
+                    "example": "editor@example.com"
+                },
+                "created_at": {
                     "type": "string"
                 },
+                "reason": {
+                    "type": "string",
+                    "example": "fixed typo in chorus"
+                },
+                "revision": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "snapshot": {
+                    "description": "Song fields, left out of revision lists",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SongSnapshot"
+                        }
+                    ]
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "song_version": {
+                    "type": "integer",
+                    "example": 7
+                }
+            }
+        },
+        "model.SongSnapshot": {
+            "type": "object",
+            "properties": {
+                "credits": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Credit"
+                    }
+                },
+                "genres": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "rock",
+                        "alternative"
+                    ]
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "link": {
+                    "type": "string",
+                    "example": "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+                },
+                "release_date": {
+                    "type": "string",
+                    "example": "2006-07-16"
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "space",
+                        "falsetto"
+                    ]
+                },
                 "text": {
                     "type": "string"
+                }
+            }
+        },
+        "model.TimedLine": {
+            "type": "object",
+            "properties": {
+                "end_ms": {
+                    "description": "End of last word, set by enhanced LRC only",
+                    "type": "integer",
+                    "example": 15200
                 },
-                "updatedAt": {
-                    "type": "string"
+                "index": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "start_ms": {
+                    "type": "integer",
+                    "example": 12500
+                },
+                "text": {
+                    "type": "string",
+                    "example": "You set my soul alight"
+                },
+                "words": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TimedWord"
+                    }
+                }
+            }
+        },
+        "model.TimedWord": {
+            "type": "object",
+            "properties": {
+                "start_ms": {
+                    "type": "integer",
+                    "example": 13100
+                },
+                "text": {
+                    "type": "string",
+                    "example": "soul"
+                }
+            }
+        },
+        "model.Track": {
+            "type": "object",
+            "properties": {
+                "disc_number": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "group": {
+                    "type": "string",
+                    "example": "Muse"
+                },
+                "song": {
+                    "type": "string",
+                    "example": "Supermassive Black Hole"
+                },
+                "song_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "track_number": {
+                    "type": "integer",
+                    "example": 2
                 }
             }
         }
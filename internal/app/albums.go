@@ -0,0 +1,343 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ListAlbums godoc
+//
+//	@Summary		List albums
+//	@Description	Lists albums newest added first, next page is fetched with next_cursor of previous response
+//	@Tags			albums
+//	@Produce		json
+//	@Param			artist_id	query		int		false	"Only albums of artist"
+//	@Param			cursor		query		string	false	"Cursor of requested page"
+//	@Param			limit		query		int		false	"Number of albums on page"
+//	@Success		200	{object}	domain.AlbumPage
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/albums [get]
+func (s *Server) ListAlbums(c *gin.Context) {
+	var artistID *uint64
+
+	l := c.DefaultQuery("limit", "0")
+
+	limit, err := strconv.ParseUint(l, 10, 32)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if a, ok := c.GetQuery("artist_id"); ok {
+		id, err := strconv.ParseUint(a, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		artistID = &id
+	}
+
+	page, err := s.albumService.List(c.Request.Context(), artistID, c.Query("cursor"), uint(limit))
+
+	if errors.Is(err, domain.ErrPageLimit) || errors.Is(err, domain.ErrInvalidCursor) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// AddAlbum godoc
+//
+//	@Summary		Add album
+//	@Description	Adds album of existing artist, tracks are set separately
+//	@Tags			albums
+//	@Accept			json
+//	@Produce		json
+//	@Param			message	body		domain.AlbumRequest	true	"Add album request"
+//	@Success		201	{object}	model.Album
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		422	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/albums [post]
+func (s *Server) AddAlbum(c *gin.Context) {
+	var request domain.AlbumRequest
+
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("add album request: ", request)
+
+	err := validator.New().Struct(request)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("validation errors: %s", errors)})
+		return
+	}
+
+	album := albumFromRequest(request)
+
+	err = s.albumService.Add(c.Request.Context(), album)
+
+	if abortAlbumError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	album, err = s.albumService.Get(c.Request.Context(), album.ID)
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Header("Location", fmt.Sprintf("/albums/%d", album.ID))
+	c.JSON(http.StatusCreated, album)
+}
+
+// GetAlbum godoc
+//
+//	@Summary		Get album
+//	@Description	Gets album with its track listing
+//	@Tags			albums
+//	@Produce		json
+//	@Param			album_id	path		int	true	"Album ID"
+//	@Success		200	{object}	model.Album
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/albums/{album_id} [get]
+func (s *Server) GetAlbum(c *gin.Context) {
+	albumID, ok := albumIDParam(c)
+	if !ok {
+		return
+	}
+
+	album, err := s.albumService.Get(c.Request.Context(), albumID)
+
+	if abortAlbumError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// ModifyAlbum godoc
+//
+//	@Summary		Modify album
+//	@Description	Replaces album fields, track listing is kept
+//	@Tags			albums
+//	@Accept			json
+//	@Produce		json
+//	@Param			album_id	path		int					true	"Album ID"
+//	@Param			message		body		domain.AlbumRequest	true	"Modify album request"
+//	@Success		200	{object}	model.Album
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		422	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/albums/{album_id} [put]
+func (s *Server) ModifyAlbum(c *gin.Context) {
+	var request domain.AlbumRequest
+
+	albumID, ok := albumIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("modify album request: ", albumID, " ", request)
+
+	err := validator.New().Struct(request)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("validation errors: %s", errors)})
+		return
+	}
+
+	album := albumFromRequest(request)
+	album.ID = albumID
+
+	err = s.albumService.Modify(c.Request.Context(), album)
+
+	if abortAlbumError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	album, err = s.albumService.Get(c.Request.Context(), albumID)
+
+	if abortAlbumError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// SetAlbumTracks godoc
+//
+//	@Summary		Set album tracks
+//	@Description	Replaces track listing of album, tracks are numbered in given order on each disc. Used to add, remove and reorder tracks
+//	@Tags			albums
+//	@Accept			json
+//	@Produce		json
+//	@Param			album_id	path		int							true	"Album ID"
+//	@Param			message		body		domain.SetTracksRequest	true	"Album tracks request"
+//	@Success		200	{object}	model.Album
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		422	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/albums/{album_id}/tracks [put]
+func (s *Server) SetAlbumTracks(c *gin.Context) {
+	var request domain.SetTracksRequest
+
+	albumID, ok := albumIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("set album tracks request: ", albumID, " ", request)
+
+	err := validator.New().Struct(request)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("validation errors: %s", errors)})
+		return
+	}
+
+	album, err := s.albumService.SetTracks(c.Request.Context(), albumID, request.Tracks)
+
+	if abortAlbumError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, album)
+}
+
+// DeleteAlbum godoc
+//
+//	@Summary		Delete album
+//	@Description	Deletes album, its songs are kept
+//	@Tags			albums
+//	@Produce		json
+//	@Param			album_id	path		int		true	"Album ID"
+//	@Success		200
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/albums/{album_id} [delete]
+func (s *Server) DeleteAlbum(c *gin.Context) {
+	albumID, ok := albumIDParam(c)
+	if !ok {
+		return
+	}
+
+	s.log.Debug("request to delete album id: ", albumID)
+
+	err := s.albumService.Remove(c.Request.Context(), albumID)
+
+	if abortAlbumError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+func albumFromRequest(request domain.AlbumRequest) *model.Album {
+	return &model.Album{
+		Title:       request.Title,
+		ArtistID:    request.ArtistID,
+		ReleaseDate: request.ReleaseDate,
+		Type:        request.Type,
+		CoverLink:   request.CoverLink,
+	}
+}
+
+// Parses album ID path parameter, responds with error if it's invalid
+func albumIDParam(c *gin.Context) (uint64, bool) {
+	albumID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, false
+	}
+
+	return albumID, true
+}
+
+// Responds with error matching album change failure, returns false for other errors.
+// Artist and songs referenced by request must exist
+func abortAlbumError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrAlbumNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrDuplicateTrack):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrArtistNotFound), errors.Is(err, domain.ErrSongNotFound):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,258 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ListArtists godoc
+//
+//	@Summary		List artists
+//	@Description	Lists artists ordered by slug, next page is fetched with next_cursor of previous response
+//	@Tags			artists
+//	@Produce		json
+//	@Param			cursor	query		string	false	"Cursor of requested page"
+//	@Param			limit	query		int		false	"Number of artists on page"
+//	@Success		200	{object}	domain.ArtistPage
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/artists [get]
+func (s *Server) ListArtists(c *gin.Context) {
+	l := c.DefaultQuery("limit", "0")
+
+	limit, err := strconv.ParseUint(l, 10, 32)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := s.artistService.List(c.Request.Context(), c.Query("cursor"), uint(limit))
+
+	if errors.Is(err, domain.ErrPageLimit) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetArtist godoc
+//
+//	@Summary		Get artist
+//	@Description	Gets artist with aliases and number of songs
+//	@Tags			artists
+//	@Produce		json
+//	@Param			artist_id	path		string	true	"Artist ID or slug"
+//	@Success		200	{object}	model.Artist
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/artists/{artist_id} [get]
+func (s *Server) GetArtist(c *gin.Context) {
+	var (
+		artist *model.Artist
+		err    error
+	)
+
+	i := c.Param("id")
+
+	// Artist is addressed by slug too
+	if artistID, convErr := strconv.ParseUint(i, 10, 64); convErr == nil {
+		artist, err = s.artistService.Get(c.Request.Context(), artistID)
+	} else {
+		artist, err = s.artistService.GetBySlug(c.Request.Context(), i)
+	}
+
+	if errors.Is(err, domain.ErrArtistNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+// RenameArtist godoc
+//
+//	@Summary		Rename artist
+//	@Description	Changes canonical name and slug of artist, old name is kept as alias. Songs of artist are renamed too
+//	@Tags			artists
+//	@Accept			json
+//	@Produce		json
+//	@Param			artist_id	path		int							true	"Artist ID"
+//	@Param			message		body		domain.RenameArtistRequest	true	"Rename artist request"
+//	@Success		200	{object}	model.Artist
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/artists/{artist_id} [put]
+func (s *Server) RenameArtist(c *gin.Context) {
+	var request domain.RenameArtistRequest
+
+	artistID, ok := artistIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("rename artist request: ", artistID, " ", request)
+
+	err := validator.New().Struct(request)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("validation errors: %s", errors)})
+		return
+	}
+
+	artist, err := s.artistService.Rename(c.Request.Context(), artistID, request.Name)
+
+	if abortArtistError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+// MergeArtistAliases godoc
+//
+//	@Summary		Merge artist aliases
+//	@Description	Adds aliases songs are resolved to artist by. Artists already known by any of aliases are merged into this one with their songs and aliases
+//	@Tags			artists
+//	@Accept			json
+//	@Produce		json
+//	@Param			artist_id	path		int							true	"Artist ID"
+//	@Param			message		body		domain.MergeAliasesRequest	true	"Merge aliases request"
+//	@Success		200	{object}	model.Artist
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/artists/{artist_id}/aliases [post]
+func (s *Server) MergeArtistAliases(c *gin.Context) {
+	var request domain.MergeAliasesRequest
+
+	artistID, ok := artistIDParam(c)
+	if !ok {
+		return
+	}
+
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("merge artist aliases request: ", artistID, " ", request)
+
+	err := validator.New().Struct(request)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("validation errors: %s", errors)})
+		return
+	}
+
+	artist, err := s.artistService.MergeAliases(c.Request.Context(), artistID, request.Aliases)
+
+	if abortArtistError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, artist)
+}
+
+// DeleteArtist godoc
+//
+//	@Summary		Delete artist
+//	@Description	Deletes artist which has no songs and albums
+//	@Tags			artists
+//	@Produce		json
+//	@Param			artist_id	path		int		true	"Artist ID"
+//	@Success		200
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/artists/{artist_id} [delete]
+func (s *Server) DeleteArtist(c *gin.Context) {
+	artistID, ok := artistIDParam(c)
+	if !ok {
+		return
+	}
+
+	s.log.Debug("request to delete artist id: ", artistID)
+
+	err := s.artistService.Remove(c.Request.Context(), artistID)
+
+	if abortArtistError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Parses artist ID path parameter, responds with error if it's invalid
+func artistIDParam(c *gin.Context) (uint64, bool) {
+	artistID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, false
+	}
+
+	return artistID, true
+}
+
+// Responds with error matching artist change failure, returns false for other errors
+func abortArtistError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrArtistNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidName):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrAliasTaken),
+		errors.Is(err, domain.ErrArtistHasSongs),
+		errors.Is(err, domain.ErrDuplicateSong):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,167 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// ImportChordPro godoc
+//
+//	@Summary		Upload chord sheet
+//	@Description	Replaces chord sheet of song with ChordPro document. Chords, {key} and {capo} are validated,
+//	@Description	sections are marked by {start_of_chorus}, {start_of_verse}, {start_of_bridge} and {start_of_tab}
+//	@Tags			chords
+//	@Accept			plain
+//	@Produce		json
+//	@Param			song_id	path		int		true	"Song ID"
+//	@Param			message	body		string	true	"ChordPro document"
+//	@Success		200	{object}	model.ChordSheet
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		422	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/chords [put]
+func (s *Server) ImportChordPro(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	source, err := c.GetRawData()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("import chordpro request: ", songID, " ", len(source), " bytes")
+
+	sheet, err := s.songService.ImportChordPro(c.Request.Context(), songID, string(source))
+
+	if abortChordError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, sheet)
+}
+
+// GetChordSheet godoc
+//
+//	@Summary		Get chord sheet
+//	@Description	Renders chord sheet of song as plain text with chords above lyrics or as JSON.
+//	@Description	Chords can be transposed, transposed chords are spelled by key unless accidentals are set
+//	@Tags			chords
+//	@Produce		plain,json
+//	@Param			song_id		path		int		true	"Song ID"
+//	@Param			format		query		string	false	"Format, text by default"	Enums(text, json)
+//	@Param			transpose	query		int		false	"Semitones to transpose by, from -11 to 11"
+//	@Param			accidentals	query		string	false	"Spelling of transposed chords"	Enums(sharp, flat)
+//	@Success		200	{object}	model.ChordSheet
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/chords [get]
+func (s *Server) GetChordSheet(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	transpose, err := strconv.Atoi(c.DefaultQuery("transpose", "0"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	query := domain.ChordSheetQuery{
+		Format:      c.Query("format"),
+		Transpose:   transpose,
+		Accidentals: c.Query("accidentals"),
+	}
+
+	rendered, err := s.songService.ChordSheet(c.Request.Context(), songID, query)
+
+	switch {
+	case abortChordError(c, err):
+		return
+	case errors.Is(err, domain.ErrChordFormat),
+		errors.Is(err, domain.ErrAccidentals),
+		errors.Is(err, domain.ErrTranspose):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	if rendered.Sheet != nil {
+		c.JSON(http.StatusOK, rendered.Sheet)
+		return
+	}
+
+	// Overrides JSON content type set by middleware
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.String(http.StatusOK, rendered.Text)
+}
+
+// DeleteChordSheet godoc
+//
+//	@Summary		Delete chord sheet
+//	@Description	Removes chord sheet of song, lyrics are kept
+//	@Tags			chords
+//	@Produce		json
+//	@Param			song_id	path		int	true	"Song ID"
+//	@Success		200
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/chords [delete]
+func (s *Server) DeleteChordSheet(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("request to delete chord sheet of song id: ", songID)
+
+	err = s.songService.RemoveChordSheet(c.Request.Context(), songID)
+
+	if abortChordError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Responds with error matching chord sheet failure, returns false for other errors
+func abortChordError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrSongNotFound), errors.Is(err, domain.ErrNoChordSheet):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidChordPro):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+
+	return true
+}
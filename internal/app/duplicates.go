@@ -0,0 +1,177 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ListDuplicates godoc
+//
+//	@Summary		Find duplicate songs
+//	@Description	Reports pairs of songs with similar names, scored by similarity of names, groups and lyrics. Most likely duplicates come first
+//	@Tags			duplicates
+//	@Produce		json
+//	@Param			threshold	query		number	false	"Minimal name similarity, from 0 to 1"
+//	@Param			limit		query		int		false	"Number of reported pairs"
+//	@Success		200	{object}	domain.DuplicateReport
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/songs/duplicates [get]
+func (s *Server) ListDuplicates(c *gin.Context) {
+	var threshold *float64
+
+	limit, err := strconv.ParseUint(c.DefaultQuery("limit", "0"), 10, 32)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if t, ok := c.GetQuery("threshold"); ok {
+		value, err := strconv.ParseFloat(t, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		threshold = &value
+	}
+
+	report, err := s.songService.Duplicates(c.Request.Context(), threshold, uint(limit))
+
+	if errors.Is(err, domain.ErrThreshold) || errors.Is(err, domain.ErrPageLimit) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// MergeSong godoc
+//
+//	@Summary		Merge duplicate song
+//	@Description	Merges duplicate into song: albums, credits, genres, tags and revisions of duplicate are moved to song,
+//	@Description	synchronized lyrics and chord sheet are moved when song has none. Duplicate is removed and its ID redirects to song
+//	@Tags			duplicates
+//	@Accept			json
+//	@Produce		json
+//	@Param			song_id			path		int							true	"ID of kept song"
+//	@Param			message			body		domain.MergeSongsRequest	true	"Merge song request"
+//	@Param			If-Match		header		string	false	"ETag of kept song version"
+//	@Param			X-Author		header		string	false	"Author of change saved with song revision"
+//	@Param			X-Change-Reason	header		string	false	"Reason of change saved with song revision"
+//	@Success		200	{object}	model.Song
+//	@Header			200	{string}	ETag	"New song entity tag"
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		412	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/merge [post]
+func (s *Server) MergeSong(c *gin.Context) {
+	var request domain.MergeSongsRequest
+
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	err = validator.New().Struct(request)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("validation errors: %s", errors)})
+		return
+	}
+
+	version, err := ifMatchVersion(c.GetHeader("If-Match"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("merge song request: ", songID, " ", request)
+
+	song, err := s.songService.Merge(c.Request.Context(), songID, request.DuplicateID, version, changeNote(c))
+
+	if abortDuplicateSong(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrMergeSelf):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrSongNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrVersionMismatch):
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Header("ETag", versionETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
+
+// Responds with conflict and ID of existing song when change duplicates it, returns false for other errors
+func abortDuplicateSong(c *gin.Context, err error) bool {
+	if !errors.Is(err, domain.ErrDuplicateSong) {
+		return false
+	}
+
+	response := domain.DuplicateSongResponse{
+		Error: err.Error(),
+	}
+
+	var duplicateErr *domain.DuplicateSongError
+	if errors.As(err, &duplicateErr) {
+		response.ExistingID = duplicateErr.ExistingID
+	}
+
+	c.AbortWithStatusJSON(http.StatusConflict, response)
+
+	return true
+}
+
+// Redirects request of merged song to song it was merged into, returns false when song wasn't merged
+func (s *Server) redirectMerged(c *gin.Context, songID uint64) bool {
+	target, err := s.songService.MergedInto(c.Request.Context(), songID)
+	if err != nil {
+		if !errors.Is(err, domain.ErrSongNotFound) {
+			s.log.Error(err)
+		}
+
+		return false
+	}
+
+	location := fmt.Sprintf("/song/%d", target)
+	if len(c.Request.URL.RawQuery) > 0 {
+		location += "?" + c.Request.URL.RawQuery
+	}
+
+	c.Redirect(http.StatusMovedPermanently, location)
+	c.Abort()
+
+	return true
+}
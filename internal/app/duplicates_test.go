@@ -0,0 +1,90 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/pkg/errors"
+)
+
+func TestDuplicateSongConflict(t *testing.T) {
+	// Repository wraps uniqueness error with operation name
+	duplicate := errors.Wrap(&domain.DuplicateSongError{ExistingID: 12}, "repository.Update")
+
+	songService := &stubSongService{
+		add: func(*model.Song, domain.ChangeNote) error {
+			return duplicate
+		},
+		modify: func(uint64, uint64, domain.UpdateSongRequest, domain.ChangeNote) (uint64, error) {
+			return 0, duplicate
+		},
+		restore: func(uint64) (*model.Song, error) {
+			return nil, duplicate
+		},
+	}
+
+	r := newTestServer(t, songService)
+
+	requests := []struct {
+		method string
+		target string
+		body   string
+	}{
+		{method: http.MethodPost, target: "/song", body: `{"song":"Uprising","group":"Muse"}`},
+		{method: http.MethodPut, target: "/song/31", body: `{"song":"Uprising","group":"Muse","link":"https://example.com"}`},
+		{method: http.MethodPost, target: "/song/31/restore"},
+	}
+
+	for _, req := range requests {
+		t.Run(req.method+" "+req.target, func(t *testing.T) {
+			w := serve(r, req.method, req.target, req.body, nil)
+
+			if w.Code != http.StatusConflict {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, http.StatusConflict, w.Body)
+			}
+
+			var response domain.DuplicateSongResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+
+			if response.ExistingID != 12 || len(response.Error) == 0 {
+				t.Errorf("response = %+v, want existing id 12 with error", response)
+			}
+		})
+	}
+}
+
+func TestGetMergedSong(t *testing.T) {
+	songService := &stubSongService{
+		get: func(uint64, []string) (*model.Song, error) {
+			return nil, domain.ErrSongNotFound
+		},
+		mergedInto: func(songID uint64) (uint64, error) {
+			if songID == 31 {
+				return 12, nil
+			}
+
+			return 0, domain.ErrSongNotFound
+		},
+	}
+
+	r := newTestServer(t, songService)
+
+	w := serve(r, http.MethodGet, "/song/31?fields=song", "", nil)
+
+	if w.Code != http.StatusMovedPermanently {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusMovedPermanently)
+	}
+
+	if got := w.Header().Get("Location"); got != "/song/12?fields=song" {
+		t.Errorf("Location = %q, want %q", got, "/song/12?fields=song")
+	}
+
+	if w := serve(r, http.MethodGet, "/song/40", "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("status of missing song = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
@@ -0,0 +1,58 @@
+package app
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var errBadIfMatch = errors.New("invalid If-Match header, expected single strong song ETag or *")
+
+// Returns entity tag of song version
+func versionETag(version uint64) string {
+	return `"` + strconv.FormatUint(version, 10) + `"`
+}
+
+// Extracts song version from If-Match header, returns zero if any version matches
+func ifMatchVersion(header string) (uint64, error) {
+	header = strings.TrimSpace(header)
+
+	if len(header) == 0 || header == "*" {
+		return 0, nil
+	}
+
+	// Weak tags never match
+	tag, ok := strings.CutPrefix(header, `"`)
+	if !ok {
+		return 0, errBadIfMatch
+	}
+
+	tag, ok = strings.CutSuffix(tag, `"`)
+	if !ok {
+		return 0, errBadIfMatch
+	}
+
+	version, err := strconv.ParseUint(tag, 10, 64)
+	if err != nil || version == 0 {
+		return 0, errBadIfMatch
+	}
+
+	return version, nil
+}
+
+// Checks whether If-None-Match header value matches entity tag, weak comparison is used
+func noneMatch(header, etag string) bool {
+	if len(header) == 0 {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+
+	return false
+}
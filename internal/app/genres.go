@@ -0,0 +1,126 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// ListGenres godoc
+//
+//	@Summary		List genres
+//	@Description	Lists genre vocabulary ordered by name with number of songs
+//	@Tags			genres
+//	@Produce		json
+//	@Success		200	{array}		model.Genre
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/genres [get]
+func (s *Server) ListGenres(c *gin.Context) {
+	genres, err := s.genreService.List(c.Request.Context())
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, genres)
+}
+
+// AddGenre godoc
+//
+//	@Summary		Add genre
+//	@Description	Adds genre to vocabulary, its slug is made of name
+//	@Tags			genres
+//	@Accept			json
+//	@Produce		json
+//	@Param			message	body		domain.AddGenreRequest	true	"Add genre request"
+//	@Success		201	{object}	model.Genre
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/genres [post]
+func (s *Server) AddGenre(c *gin.Context) {
+	var request domain.AddGenreRequest
+
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("add genre request: ", request)
+
+	err := validator.New().Struct(request)
+	if err != nil {
+		errors := err.(validator.ValidationErrors)
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("validation errors: %s", errors)})
+		return
+	}
+
+	genre, err := s.genreService.Add(c.Request.Context(), request.Name)
+
+	if errors.Is(err, domain.ErrInvalidName) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, domain.ErrGenreExists) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, genre)
+}
+
+// DeleteGenre godoc
+//
+//	@Summary		Delete genre
+//	@Description	Deletes genre which isn't set on any song
+//	@Tags			genres
+//	@Produce		json
+//	@Param			genre_id	path		int		true	"Genre ID"
+//	@Success		200
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/genres/{genre_id} [delete]
+func (s *Server) DeleteGenre(c *gin.Context) {
+	genreID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("request to delete genre id: ", genreID)
+
+	err = s.genreService.Remove(c.Request.Context(), genreID)
+
+	if errors.Is(err, domain.ErrGenreNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, domain.ErrGenreInUse) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
@@ -3,25 +3,32 @@ package app
 import (
 	"errors"
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/metadata"
 	"github.com/Sadere/song-depository/internal/model"
+	"github.com/Sadere/song-depository/internal/patch"
+	"github.com/Sadere/song-depository/internal/search"
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
+// Patch formats supported by song resource
+const acceptPatch = domain.MergePatchContentType + ", " + domain.JSONPatchContentType
+
 // ListSongs godoc
 //
 //	@Summary		List songs
-//	@Description	list songs based on filter and page
+//	@Description	list songs based on filter, pages are fetched with next_cursor and prev_cursor of previous response. Songs are ordered by sort fields, search hits are ordered by relevance by default
 //	@Tags			songs
 //	@Accept			json
 //	@Produce		json
 //	@Param			message	body	domain.ListSongsRequest	true	"List songs request"
-//	@Success		200	{array}		model.Song
-//	@Success		204
+//	@Success		200	{object}	domain.SongPage
 //	@Failure		400	{object}	ErrorResponse
 //	@Failure		500	{object}	ErrorResponse
 //	@Router			/list-songs [post]
@@ -35,10 +42,17 @@ func (s *Server) ListSongs(c *gin.Context) {
 
 	s.log.Debug("list song request: ", request)
 
-	songs, err := s.songService.List(request.Filter, request.Page)
+	page, err := s.songService.List(c.Request.Context(), request.Filter, domain.ListOptions{
+		Cursor:    request.Cursor,
+		Limit:     request.Limit,
+		Sort:      request.Sort,
+		Fields:    model.FieldSet{}.With(request.Fields...),
+		Total:     request.Total,
+		Highlight: request.Highlight,
+	})
 
-	if errors.Is(err, domain.ErrNoSongs) {
-		c.AbortWithStatus(http.StatusNoContent)
+	if isFilterError(err) || isPageError(err) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -47,21 +61,62 @@ func (s *Server) ListSongs(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"songs": songs})
+	c.JSON(http.StatusOK, page)
+}
+
+// SongFacets godoc
+//
+//	@Summary		Song facets
+//	@Description	Counts songs matching filter per genre, tag, artist and release year
+//	@Tags			songs
+//	@Accept			json
+//	@Produce		json
+//	@Param			message	body	domain.SongFacetsRequest	true	"Song facets request"
+//	@Success		200	{object}	domain.SongFacets
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/songs/facets [post]
+func (s *Server) SongFacets(c *gin.Context) {
+	var request domain.SongFacetsRequest
+
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("song facets request: ", request)
+
+	facets, err := s.songService.Facets(c.Request.Context(), request.Filter)
+
+	if isFilterError(err) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, facets)
 }
 
 // AddSong godoc
 //
 //	@Summary		Add song
-//	@Description	Add new song to depository
+//	@Description	Add new song to depository, its details are looked up in background
 //	@Tags			songs
 //	@Accept			json
 //	@Produce		json
-//	@Param			message	body		domain.AddSongRequest	true	"Add new song request"
-//	@Success		201
+//	@Param			message			body		domain.AddSongRequest	true	"Add new song request"
+//	@Param			X-Author		header		string	false	"Author of change saved with song revision"
+//	@Param			X-Change-Reason	header		string	false	"Reason of change saved with song revision"
+//	@Success		202	{object}	domain.AddSongResponse
+//	@Header			202	{string}	Location	"Song enrichment status URL"
 //	@Failure		400	{object}	ErrorResponse
+//	@Failure		409	{object}	DuplicateSongResponse
 //	@Failure		500	{object}	ErrorResponse
-//	@Failure		503	{object}	ErrorResponse
 //	@Router			/song [post]
 func (s *Server) AddSong(c *gin.Context) {
 	var request domain.AddSongRequest
@@ -87,19 +142,137 @@ func (s *Server) AddSong(c *gin.Context) {
 		Group: request.Group,
 	}
 
-	err = s.songService.Add(&song)
+	err = s.songService.Add(c.Request.Context(), &song, changeNote(c))
 
-	if errors.Is(err, domain.ErrSongDetail) {
-		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	if abortDuplicateSong(c, err) {
+		return
+	}
+
+	if errors.Is(err, domain.ErrInvalidName) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	if err != nil {
-		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	statusURL := fmt.Sprintf("/song/%d/status", song.ID)
+
+	c.Header("Location", statusURL)
+	c.JSON(http.StatusAccepted, domain.AddSongResponse{
+		ID:        song.ID,
+		Status:    string(song.EnrichmentStatus),
+		StatusURL: statusURL,
+	})
+}
+
+// GetSong godoc
+//
+//	@Summary		Get song
+//	@Description	Gets song with all its info or selected fields only, supports conditional requests with If-None-Match.
+//	@Description	Merged song redirects to song it was merged into
+//	@Tags			songs
+//	@Produce		json
+//	@Param			song_id			path		int		true	"Song ID"
+//	@Param			fields			query		string	false	"Comma separated song fields to return, text_length and verse_count can be requested too"
+//	@Param			If-None-Match	header		string	false	"ETag of cached song"
+//	@Success		200	{object}	model.Song
+//	@Header			200	{string}	ETag	"Song entity tag, weak for selected fields"
+//	@Success		304
+//	@Header			301	{string}	Location	"URL of song merged song was merged into"
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id} [get]
+func (s *Server) GetSong(c *gin.Context) {
+	i := c.Param("id")
+
+	songID, err := strconv.Atoi(i)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	fields := splitFields(c.Query("fields"))
+
+	song, err := s.songService.Get(c.Request.Context(), uint64(songID), fields)
+
+	if errors.Is(err, domain.ErrSongNotFound) {
+		if s.redirectMerged(c, uint64(songID)) {
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, domain.ErrUnknownField) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	// Partial song can't be used as precondition of edit
+	etag := versionETag(song.Version)
+	if len(fields) > 0 {
+		etag = "W/" + etag
+	}
+
+	c.Header("ETag", etag)
+	c.Header("Accept-Patch", acceptPatch)
+
+	// Client has actual version
+	if noneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.AbortWithStatus(http.StatusNotModified)
 		return
 	}
 
-	c.Status(http.StatusCreated)
+	c.JSON(http.StatusOK, model.PartialSong{Song: song, Fields: fields})
+}
+
+// GetSongStatus godoc
+//
+//	@Summary		Get song enrichment status
+//	@Description	Reports whether song details were looked up
+//	@Tags			songs
+//	@Produce		json
+//	@Param			song_id	path		int		true	"Song ID"
+//	@Success		200	{object}	model.EnrichmentState
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/status [get]
+func (s *Server) GetSongStatus(c *gin.Context) {
+	i := c.Param("id")
+
+	songID, err := strconv.Atoi(i)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	state, err := s.songService.EnrichmentState(c.Request.Context(), uint64(songID))
+
+	if errors.Is(err, domain.ErrSongNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, state)
 }
 
 // ModifySong godoc
@@ -109,11 +282,17 @@ func (s *Server) AddSong(c *gin.Context) {
 //	@Tags			songs
 //	@Accept			json
 //	@Produce		json
-//	@Param			song_id	path		int		true	"Song ID"
-//	@Param			message	body		domain.UpdateSongRequest	true	"Edit song request"
+//	@Param			song_id		path		int		true	"Song ID"
+//	@Param			If-Match	header		string	false	"ETag of edited song version"
+//	@Param			message		body		domain.UpdateSongRequest	true	"Edit song request"
+//	@Param			X-Author		header		string	false	"Author of change saved with song revision"
+//	@Param			X-Change-Reason	header		string	false	"Reason of change saved with song revision"
 //	@Success		200
+//	@Header			200	{string}	ETag	"New song entity tag"
 //	@Failure		400	{object}	ErrorResponse
 //	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	DuplicateSongResponse
+//	@Failure		412	{object}	ErrorResponse
 //	@Failure		500	{object}	ErrorResponse
 //	@Router			/song/{song_id} [put]
 func (s *Server) ModifySong(c *gin.Context) {
@@ -143,32 +322,143 @@ func (s *Server) ModifySong(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c.GetHeader("If-Match"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Modify song
-	err = s.songService.Modify(uint64(songID), request)
+	newVersion, err := s.songService.Modify(c.Request.Context(), uint64(songID), version, request, changeNote(c))
+
+	if abortDuplicateSong(c, err) {
+		return
+	}
 
 	if errors.Is(err, domain.ErrSongNotFound) {
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
+	if errors.Is(err, domain.ErrVersionMismatch) {
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	}
+
+	if errors.Is(err, domain.ErrInvalidName) || errors.Is(err, domain.ErrGenreNotFound) || errors.Is(err, domain.ErrInvalidTag) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err != nil {
 		s.log.Error(err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
 		return
 	}
 
+	c.Header("ETag", versionETag(newVersion))
 	c.Status(http.StatusOK)
 }
 
+// PatchSong godoc
+//
+//	@Summary		Patch song
+//	@Description	Applies JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) to song, null or removed fields are cleared.
+//	@Description	Credits are matched to artists by name, primary credit follows group
+//	@Tags			songs
+//	@Accept			application/merge-patch+json,application/json-patch+json
+//	@Produce		json
+//	@Param			song_id		path		int		true	"Song ID"
+//	@Param			If-Match	header		string	false	"ETag of patched song version"
+//	@Param			message		body		object	true	"Patch document"
+//	@Param			X-Author		header		string	false	"Author of change saved with song revision"
+//	@Param			X-Change-Reason	header		string	false	"Reason of change saved with song revision"
+//	@Success		200	{object}	model.Song
+//	@Header			200	{string}	ETag	"New song entity tag"
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		412	{object}	ErrorResponse
+//	@Failure		415	{object}	ErrorResponse
+//	@Failure		422	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id} [patch]
+func (s *Server) PatchSong(c *gin.Context) {
+	i := c.Param("id")
+
+	songID, err := strconv.Atoi(i)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	version, err := ifMatchVersion(c.GetHeader("If-Match"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	patchDoc, err := c.GetRawData()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("patch song request: ", songID, " ", string(patchDoc))
+
+	song, err := s.songService.Patch(c.Request.Context(), uint64(songID), version, c.ContentType(), patchDoc, changeNote(c))
+
+	if abortDuplicateSong(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrPatchType):
+		c.Header("Accept-Patch", acceptPatch)
+		c.AbortWithStatusJSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrSongNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrVersionMismatch):
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, patch.ErrInvalidPatch):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, patch.ErrTestFailed):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, patch.ErrInvalidPath),
+		errors.Is(err, domain.ErrReadOnlyField),
+		errors.Is(err, domain.ErrInvalidSong),
+		errors.Is(err, domain.ErrInvalidName),
+		errors.Is(err, domain.ErrCreditRole),
+		errors.Is(err, domain.ErrGenreNotFound),
+		errors.Is(err, domain.ErrInvalidTag):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Header("ETag", versionETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
+
 // GetSongText godoc
 //
 //	@Summary		Get song text
-//	@Description	Gets specific verse of requested song's text
+//	@Description	Gets sections of requested song's text: one by index, all of type or all with label. First section is returned by default
 //	@Tags			songs
 //	@Produce		json
 //	@Param			id			query		int		true	"Song ID"
-//	@Param			verse		query		int		false	"Number of verse to return (starting at 0)"
-//	@Success		200
+//	@Param			verse		query		int		false	"Index of section to return (starting at 0)"
+//	@Param			type		query		string	false	"Type of sections to return: verse, chorus, bridge, intro or outro"
+//	@Param			label		query		string	false	"Label of sections to return, e.g. Chorus or Verse 2"
+//	@Success		200	{object}	domain.SongTextResponse
 //	@Failure		400	{object}	ErrorResponse
 //	@Failure		404	{object}	ErrorResponse
 //	@Failure		500	{object}	ErrorResponse
@@ -195,11 +485,22 @@ func (s *Server) GetSongText(c *gin.Context) {
 		return
 	}
 
+	query := domain.SectionQuery{
+		Index: &verse,
+		Type:  model.SectionType(c.Query("type")),
+		Label: c.Query("label"),
+	}
+
 	// Fetch song text
-	text, err := s.songService.Song(uint64(songID), verse)
+	text, err := s.songService.Song(c.Request.Context(), uint64(songID), query)
 
-	// Verse not found
-	if errors.Is(err, domain.ErrVerseNotFound) {
+	if errors.Is(err, domain.ErrSongNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Section not found or unknown type
+	if errors.Is(err, domain.ErrVerseNotFound) || errors.Is(err, domain.ErrSectionType) {
 		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
@@ -211,21 +512,21 @@ func (s *Server) GetSongText(c *gin.Context) {
 	}
 
 	// Return JSON result
-	c.JSON(http.StatusOK, gin.H{
-		"text": text,
-	})
+	c.JSON(http.StatusOK, text)
 }
 
 // DeleteSong godoc
 //
 //	@Summary		Delete song
-//	@Description	Deletes song with song_id from depository
+//	@Description	Moves song with song_id to trash, it can be restored until it's purged after retention period
 //	@Tags			songs
 //	@Produce		json
-//	@Param			song_id	path		int		true	"Song ID"
+//	@Param			song_id		path		int		true	"Song ID"
+//	@Param			If-Match	header		string	false	"ETag of deleted song version"
 //	@Success		200
 //	@Failure		400	{object}	ErrorResponse
 //	@Failure		404	{object}	ErrorResponse
+//	@Failure		412	{object}	ErrorResponse
 //	@Failure		500	{object}	ErrorResponse
 //	@Router			/song/{song_id} [delete]
 func (s *Server) DeleteSong(c *gin.Context) {
@@ -237,19 +538,206 @@ func (s *Server) DeleteSong(c *gin.Context) {
 		return
 	}
 
+	version, err := ifMatchVersion(c.GetHeader("If-Match"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	s.log.Debug("request to delete song id: ", songID)
 
-	err = s.songService.Remove(uint64(songID))
+	err = s.songService.Remove(c.Request.Context(), uint64(songID), version)
 
 	if errors.Is(err, domain.ErrSongNotFound) {
 		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
 		return
 	}
 
+	if errors.Is(err, domain.ErrVersionMismatch) {
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err != nil {
+		s.log.Error(err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
 		return
 	}
 
 	c.Status(http.StatusOK)
 }
+
+// RefreshSong godoc
+//
+//	@Summary		Refresh song details
+//	@Description	Looks up song details again and reports changed fields, fields set by hand are kept unless forced
+//	@Tags			songs
+//	@Accept			json
+//	@Produce		json
+//	@Param			song_id	path		int							true	"Song ID"
+//	@Param			message	body		domain.RefreshSongRequest	false	"Refresh options"
+//	@Param			X-Author		header		string	false	"Author of change saved with song revision"
+//	@Param			X-Change-Reason	header		string	false	"Reason of change saved with song revision"
+//	@Success		200	{object}	domain.RefreshResult
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		422	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Failure		502	{object}	ErrorResponse
+//	@Failure		503	{object}	ErrorResponse
+//	@Header			503	{integer}	Retry-After	"Seconds until music info service is requested again"
+//	@Router			/song/{song_id}/refresh [post]
+func (s *Server) RefreshSong(c *gin.Context) {
+	var request domain.RefreshSongRequest
+
+	i := c.Param("id")
+
+	songID, err := strconv.Atoi(i)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Options are optional
+	if c.Request.ContentLength != 0 {
+		if err := c.BindJSON(&request); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	s.log.Debug("refresh song request: ", songID, " ", request)
+
+	result, err := s.songService.Refresh(c.Request.Context(), uint64(songID), request, changeNote(c))
+
+	if errors.Is(err, domain.ErrSongNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Song was edited while details were looked up
+	if errors.Is(err, domain.ErrVersionMismatch) {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	if abortLookupError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// RefreshSongs godoc
+//
+//	@Summary		Refresh details of many songs
+//	@Description	Refreshes songs matching filter, failures are reported per song
+//	@Tags			songs
+//	@Accept			json
+//	@Produce		json
+//	@Param			message	body		domain.RefreshSongsRequest	true	"Bulk refresh request"
+//	@Param			X-Author		header		string	false	"Author of change saved with song revision"
+//	@Param			X-Change-Reason	header		string	false	"Reason of change saved with song revision"
+//	@Success		200	{object}	domain.RefreshSongsResponse
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/songs/refresh [post]
+func (s *Server) RefreshSongs(c *gin.Context) {
+	var request domain.RefreshSongsRequest
+
+	if err := c.BindJSON(&request); err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("bulk refresh request: ", request)
+
+	results, err := s.songService.RefreshFiltered(c.Request.Context(), request, changeNote(c))
+
+	if isFilterError(err) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, domain.RefreshSongsResponse{Results: results})
+}
+
+// Status godoc
+//
+//	@Summary		Service status
+//	@Description	Reports state of external dependencies
+//	@Tags			status
+//	@Produce		json
+//	@Success		200	{object}	StatusResponse
+//	@Router			/status [get]
+func (s *Server) Status(c *gin.Context) {
+	c.JSON(http.StatusOK, domain.StatusResponse{
+		MusicInfo: s.infoProvider.BreakerStatus(),
+	})
+}
+
+// Responds with error matching song detail lookup failure, returns false for other errors
+func abortLookupError(c *gin.Context, err error) bool {
+	var openErr *metadata.CircuitOpenError
+
+	switch {
+	case errors.As(err, &openErr):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(openErr.RetryAfter.Seconds()))))
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case errors.Is(err, metadata.ErrNotFound):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, metadata.ErrMalformed), errors.Is(err, metadata.ErrRejected):
+		c.AbortWithStatusJSON(http.StatusBadGateway, gin.H{"error": err.Error()})
+	case errors.Is(err, metadata.ErrUnavailable):
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+
+	return true
+}
+
+// Reports whether error is caused by invalid song filter
+func isFilterError(err error) bool {
+	return errors.Is(err, search.ErrInvalidQuery) ||
+		errors.Is(err, domain.ErrSearchConfig) ||
+		errors.Is(err, domain.ErrMatchMode) ||
+		errors.Is(err, domain.ErrFuzzyThreshold) ||
+		errors.Is(err, domain.ErrInvalidRange)
+}
+
+// Reports whether error is caused by invalid page cursor, limit, sort or fields
+func isPageError(err error) bool {
+	return errors.Is(err, domain.ErrInvalidCursor) ||
+		errors.Is(err, domain.ErrPageLimit) ||
+		errors.Is(err, domain.ErrSortField) ||
+		errors.Is(err, domain.ErrUnknownField)
+}
+
+// Splits comma separated field names
+func splitFields(value string) []string {
+	var fields []string
+
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+
+		if len(field) > 0 {
+			fields = append(fields, field)
+		}
+	}
+
+	return fields
+}
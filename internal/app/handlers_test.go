@@ -0,0 +1,505 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Sadere/song-depository/internal/config"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/metadata"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/Sadere/song-depository/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const testAdminToken = "secret"
+
+// Song service answering with preset functions, other methods panic
+type stubSongService struct {
+	service.ISongService
+
+	add     func(song *model.Song, note domain.ChangeNote) error
+	get     func(songID uint64, fields []string) (*model.Song, error)
+	modify  func(songID, version uint64, req domain.UpdateSongRequest, note domain.ChangeNote) (uint64, error)
+	remove  func(songID, version uint64) error
+	refresh func(songID uint64, req domain.RefreshSongRequest, note domain.ChangeNote) (*domain.RefreshResult, error)
+	lyrics  func(songID uint64, query domain.LyricsQuery) (*domain.LyricsPage, error)
+
+	trash      func(opts domain.ListOptions) (*domain.SongPage, error)
+	restore    func(songID uint64) (*model.Song, error)
+	hardDelete func(songID uint64) error
+	mergedInto func(songID uint64) (uint64, error)
+}
+
+func (s *stubSongService) Add(_ context.Context, song *model.Song, note domain.ChangeNote) error {
+	return s.add(song, note)
+}
+
+func (s *stubSongService) Get(_ context.Context, songID uint64, fields []string) (*model.Song, error) {
+	return s.get(songID, fields)
+}
+
+func (s *stubSongService) Modify(_ context.Context, songID, version uint64, req domain.UpdateSongRequest, note domain.ChangeNote) (uint64, error) {
+	return s.modify(songID, version, req, note)
+}
+
+func (s *stubSongService) Remove(_ context.Context, songID, version uint64) error {
+	return s.remove(songID, version)
+}
+
+func (s *stubSongService) Refresh(_ context.Context, songID uint64, req domain.RefreshSongRequest, note domain.ChangeNote) (*domain.RefreshResult, error) {
+	return s.refresh(songID, req, note)
+}
+
+func (s *stubSongService) Lyrics(_ context.Context, songID uint64, query domain.LyricsQuery) (*domain.LyricsPage, error) {
+	return s.lyrics(songID, query)
+}
+
+func (s *stubSongService) Trash(_ context.Context, opts domain.ListOptions) (*domain.SongPage, error) {
+	return s.trash(opts)
+}
+
+func (s *stubSongService) Restore(_ context.Context, songID uint64) (*model.Song, error) {
+	return s.restore(songID)
+}
+
+func (s *stubSongService) HardDelete(_ context.Context, songID uint64) error {
+	return s.hardDelete(songID)
+}
+
+// Songs weren't merged unless test says otherwise
+func (s *stubSongService) MergedInto(_ context.Context, songID uint64) (uint64, error) {
+	if s.mergedInto == nil {
+		return 0, domain.ErrSongNotFound
+	}
+
+	return s.mergedInto(songID)
+}
+
+func newTestServer(t *testing.T, songService service.ISongService) *gin.Engine {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+
+	s := &Server{
+		config:      &config.Config{AdminToken: testAdminToken},
+		songService: songService,
+		log:         zap.NewNop().Sugar(),
+	}
+
+	r, err := s.setupRoutes()
+	if err != nil {
+		t.Fatalf("setup routes: %v", err)
+	}
+
+	return r
+}
+
+func serve(r *gin.Engine, method, target, body string, header http.Header) *httptest.ResponseRecorder {
+	var req *http.Request
+
+	if len(body) > 0 {
+		req = httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req = httptest.NewRequest(method, target, nil)
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestRefreshSongDryRun(t *testing.T) {
+	var (
+		got     domain.RefreshSongRequest
+		gotNote domain.ChangeNote
+	)
+
+	songService := &stubSongService{
+		refresh: func(songID uint64, req domain.RefreshSongRequest, note domain.ChangeNote) (*domain.RefreshResult, error) {
+			got = req
+			gotNote = note
+
+			return &domain.RefreshResult{
+				SongID: songID,
+				Changes: []domain.FieldChange{
+					{Field: "link", Old: "", New: "https://example.com"},
+					{Field: "text", Old: "hand made", New: "fetched", Protected: true},
+				},
+				Applied: !req.DryRun,
+			}, nil
+		},
+	}
+
+	header := http.Header{"X-Author": {"editor"}, "X-Change-Reason": {"check sources"}}
+
+	w := serve(newTestServer(t, songService), http.MethodPost, "/song/7/refresh", `{"dry_run":true}`, header)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d, body: %s", w.Code, http.StatusOK, w.Body)
+	}
+
+	if !got.DryRun || got.Force {
+		t.Errorf("service got request %+v, want dry run only", got)
+	}
+
+	if want := (domain.ChangeNote{Author: "editor", Reason: "check sources"}); gotNote != want {
+		t.Errorf("service got note %+v, want %+v", gotNote, want)
+	}
+
+	var result domain.RefreshResult
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+
+	want := domain.RefreshResult{
+		SongID: 7,
+		Changes: []domain.FieldChange{
+			{Field: "link", Old: "", New: "https://example.com"},
+			{Field: "text", Old: "hand made", New: "fetched", Protected: true},
+		},
+	}
+
+	if !reflect.DeepEqual(result, want) {
+		t.Errorf("response = %+v, want %+v", result, want)
+	}
+}
+
+func TestRefreshSongErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		wantStatus int
+		retryAfter string
+	}{
+		{name: "invalid id", target: "/song/abc/refresh", wantStatus: http.StatusBadRequest},
+		{name: "song not found", target: "/song/1/refresh", err: domain.ErrSongNotFound, wantStatus: http.StatusNotFound},
+		{name: "detail not found", target: "/song/1/refresh", err: metadata.ErrNotFound, wantStatus: http.StatusUnprocessableEntity},
+		{name: "malformed detail", target: "/song/1/refresh", err: metadata.ErrMalformed, wantStatus: http.StatusBadGateway},
+		{name: "rejected lookup", target: "/song/1/refresh", err: metadata.ErrRejected, wantStatus: http.StatusBadGateway},
+		{name: "provider unavailable", target: "/song/1/refresh", err: metadata.ErrUnavailable, wantStatus: http.StatusServiceUnavailable},
+		{
+			name:       "breaker open",
+			target:     "/song/1/refresh",
+			err:        &metadata.CircuitOpenError{RetryAfter: 1500 * time.Millisecond},
+			wantStatus: http.StatusServiceUnavailable,
+			retryAfter: "2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songService := &stubSongService{
+				refresh: func(uint64, domain.RefreshSongRequest, domain.ChangeNote) (*domain.RefreshResult, error) {
+					return nil, tt.err
+				},
+			}
+
+			w := serve(newTestServer(t, songService), http.MethodPost, tt.target, "", nil)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d, body: %s", w.Code, tt.wantStatus, w.Body)
+			}
+
+			if got := w.Header().Get("Retry-After"); got != tt.retryAfter {
+				t.Errorf("Retry-After = %q, want %q", got, tt.retryAfter)
+			}
+		})
+	}
+}
+
+func TestGetSongETag(t *testing.T) {
+	song := &model.Song{ID: 3, Name: "Uprising", Group: "Muse", Version: 1, ManualFields: model.FieldSet{}}
+
+	songService := &stubSongService{
+		get: func(songID uint64, _ []string) (*model.Song, error) {
+			if songID != song.ID {
+				return nil, domain.ErrSongNotFound
+			}
+
+			return song, nil
+		},
+	}
+
+	r := newTestServer(t, songService)
+
+	first := serve(r, http.MethodGet, "/song/3", "", nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", first.Code, http.StatusOK)
+	}
+
+	etag := first.Header().Get("ETag")
+	if len(etag) == 0 {
+		t.Fatal("response has no ETag")
+	}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "no precondition", wantStatus: http.StatusOK},
+		{name: "same tag", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "weak tag", ifNoneMatch: "W/" + etag, wantStatus: http.StatusNotModified},
+		{name: "tag in list", ifNoneMatch: `"stale", ` + etag, wantStatus: http.StatusNotModified},
+		{name: "any tag", ifNoneMatch: "*", wantStatus: http.StatusNotModified},
+		{name: "other tag", ifNoneMatch: `"stale"`, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := http.Header{}
+			if len(tt.ifNoneMatch) > 0 {
+				header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+
+			w := serve(r, http.MethodGet, "/song/3", "", header)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+
+			if tt.wantStatus == http.StatusNotModified && w.Body.Len() > 0 {
+				t.Errorf("not modified response has body %q", w.Body)
+			}
+
+			if tt.wantStatus == http.StatusOK && w.Body.String() != first.Body.String() {
+				t.Errorf("body = %s, want %s", w.Body, first.Body)
+			}
+		})
+	}
+
+	// Tag follows song version
+	song.Version++
+
+	if w := serve(r, http.MethodGet, "/song/3", "", http.Header{"If-None-Match": {etag}}); w.Code != http.StatusOK || w.Header().Get("ETag") == etag {
+		t.Errorf("changed song: status = %d, ETag = %q", w.Code, w.Header().Get("ETag"))
+	}
+
+	if w := serve(r, http.MethodGet, "/song/4", "", nil); w.Code != http.StatusNotFound {
+		t.Errorf("missing song: status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+}
+
+func TestIfMatchPreconditions(t *testing.T) {
+	const current uint64 = 4
+
+	// Versions received by service, zero means any
+	var gotVersion uint64
+
+	checkVersion := func(version uint64) error {
+		gotVersion = version
+
+		if version != 0 && version != current {
+			return domain.ErrVersionMismatch
+		}
+
+		return nil
+	}
+
+	songService := &stubSongService{
+		modify: func(_, version uint64, _ domain.UpdateSongRequest, _ domain.ChangeNote) (uint64, error) {
+			if err := checkVersion(version); err != nil {
+				return 0, err
+			}
+
+			return current + 1, nil
+		},
+		remove: func(_, version uint64) error {
+			return checkVersion(version)
+		},
+	}
+
+	r := newTestServer(t, songService)
+
+	tests := []struct {
+		name        string
+		ifMatch     string
+		wantStatus  int
+		wantVersion uint64
+	}{
+		{name: "no precondition", wantStatus: http.StatusOK},
+		{name: "any version", ifMatch: "*", wantStatus: http.StatusOK},
+		{name: "current version", ifMatch: `"4"`, wantStatus: http.StatusOK, wantVersion: current},
+		{name: "stale version", ifMatch: `"3"`, wantStatus: http.StatusPreconditionFailed, wantVersion: 3},
+		{name: "weak tag", ifMatch: `W/"4"`, wantStatus: http.StatusBadRequest},
+		{name: "unquoted tag", ifMatch: `4`, wantStatus: http.StatusBadRequest},
+		{name: "several tags", ifMatch: `"3", "4"`, wantStatus: http.StatusBadRequest},
+		{name: "zero version", ifMatch: `"0"`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, method := range []string{http.MethodPut, http.MethodDelete} {
+		for _, tt := range tests {
+			t.Run(method+" "+tt.name, func(t *testing.T) {
+				gotVersion = 0
+
+				var body string
+				if method == http.MethodPut {
+					body = `{"song":"Uprising","group":"Muse","link":"https://example.com"}`
+				}
+
+				header := http.Header{}
+				if len(tt.ifMatch) > 0 {
+					header.Set("If-Match", tt.ifMatch)
+				}
+
+				w := serve(r, method, "/song/1", body, header)
+
+				if w.Code != tt.wantStatus {
+					t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.wantStatus, w.Body)
+				}
+
+				if gotVersion != tt.wantVersion {
+					t.Errorf("service got version %d, want %d", gotVersion, tt.wantVersion)
+				}
+
+				wantETag := ""
+				if method == http.MethodPut && tt.wantStatus == http.StatusOK {
+					wantETag = `"5"`
+				}
+
+				if got := w.Header().Get("ETag"); got != wantETag {
+					t.Errorf("ETag = %q, want %q", got, wantETag)
+				}
+			})
+		}
+	}
+}
+
+func TestGetSongFields(t *testing.T) {
+	var (
+		gotFields []string
+		gotSong   *model.Song
+	)
+
+	songService := &stubSongService{
+		get: func(songID uint64, fields []string) (*model.Song, error) {
+			gotFields = fields
+
+			song := &model.Song{ID: songID, Version: 2, Name: "Uprising", Group: "Muse", Text: "Paranoia", ManualFields: model.FieldSet{}}
+
+			for _, field := range fields {
+				switch {
+				case field == model.FieldTextLength:
+					song.TextLength = new(int)
+					*song.TextLength = len(song.Text)
+				case !model.StoredFields.Has(field):
+					return nil, domain.ErrUnknownField
+				}
+			}
+
+			gotSong = song
+
+			return song, nil
+		},
+	}
+
+	r := newTestServer(t, songService)
+
+	// Members are checked when fields are selected, otherwise whole song is expected
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantFields []string
+		wantKeys   []string
+		wantETag   string
+	}{
+		{
+			name:       "all fields",
+			wantStatus: http.StatusOK,
+			wantETag:   `"2"`,
+		},
+		{
+			name:       "selected fields",
+			query:      "?fields=song,%20group",
+			wantStatus: http.StatusOK,
+			wantFields: []string{"song", "group"},
+			wantKeys:   []string{"group", "id", "song"},
+			wantETag:   `W/"2"`,
+		},
+		{
+			name:       "computed field",
+			query:      "?fields=song,,text_length",
+			wantStatus: http.StatusOK,
+			wantFields: []string{"song", "text_length"},
+			wantKeys:   []string{"id", "song", "text_length"},
+			wantETag:   `W/"2"`,
+		},
+		{
+			name:       "unknown field",
+			query:      "?fields=song,lyrics",
+			wantStatus: http.StatusBadRequest,
+			wantFields: []string{"song", "lyrics"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFields = nil
+
+			w := serve(r, http.MethodGet, "/song/3"+tt.query, "", nil)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.wantStatus, w.Body)
+			}
+
+			if !slices.Equal(gotFields, tt.wantFields) {
+				t.Errorf("service got fields %q, want %q", gotFields, tt.wantFields)
+			}
+
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			if got := w.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %q, want %q", got, tt.wantETag)
+			}
+
+			if tt.wantKeys == nil {
+				full, _ := json.Marshal(gotSong)
+
+				if w.Body.String() != string(full) {
+					t.Errorf("body = %s, want %s", w.Body, full)
+				}
+
+				return
+			}
+
+			var body map[string]json.RawMessage
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+
+			keys := make([]string, 0, len(body))
+			for key := range body {
+				keys = append(keys, key)
+			}
+
+			slices.Sort(keys)
+
+			if !slices.Equal(keys, tt.wantKeys) {
+				t.Errorf("response members = %q, want %q", keys, tt.wantKeys)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package app
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+)
+
+// GetLyrics godoc
+//
+//	@Summary		Get song lyrics
+//	@Description	Gets verses of song lyrics by verse range or page, or single lines by verse:line references.
+//	@Description	Verses and lines are numbered from 0. Out of range requests are answered with valid range
+//	@Tags			songs
+//	@Produce		json
+//	@Param			song_id		path		int		true	"Song ID"
+//	@Param			verse_from	query		int		false	"First verse of range"
+//	@Param			verse_to	query		int		false	"Last verse of range"
+//	@Param			page		query		int		false	"Page of verses, starting at 1"
+//	@Param			limit		query		int		false	"Number of verses on page"
+//	@Param			lines		query		string	false	"Comma separated line references, e.g. 0:1,2:0-3"
+//	@Success		200	{object}	domain.LyricsPage
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	LyricsRangeResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/lyrics [get]
+func (s *Server) GetLyrics(c *gin.Context) {
+	var query domain.LyricsQuery
+
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Checked in order so the first invalid parameter is reported
+	ints := []struct {
+		name   string
+		target **int
+	}{
+		{"verse_from", &query.VerseFrom},
+		{"verse_to", &query.VerseTo},
+	}
+
+	for _, param := range ints {
+		name, target := param.name, param.target
+
+		if v, ok := c.GetQuery(name); ok {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": name + ": " + err.Error()})
+				return
+			}
+
+			*target = &n
+		}
+	}
+
+	uints := []struct {
+		name   string
+		target *uint
+	}{
+		{"page", &query.Page},
+		{"limit", &query.Limit},
+	}
+
+	for _, param := range uints {
+		name, target := param.name, param.target
+
+		if v, ok := c.GetQuery(name); ok {
+			n, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": name + ": " + err.Error()})
+				return
+			}
+
+			*target = uint(n)
+		}
+	}
+
+	query.Lines, err = parseLineRefs(c.Query("lines"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := s.songService.Lyrics(c.Request.Context(), songID, query)
+
+	var rangeErr *domain.RangeError
+
+	switch {
+	case errors.Is(err, domain.ErrSongNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.As(err, &rangeErr):
+		c.AbortWithStatusJSON(http.StatusNotFound, LyricsRangeResponse{
+			Error: rangeErr.Error(),
+			Verse: rangeErr.Verse,
+			First: rangeErr.First,
+			Last:  rangeErr.Last,
+		})
+		return
+	case errors.Is(err, domain.ErrLyricsQuery),
+		errors.Is(err, domain.ErrInvalidRange),
+		errors.Is(err, domain.ErrPageLimit):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// Out of range lyrics request error with valid range
+type LyricsRangeResponse struct {
+	Error string `json:"error" example:"verse is out of range, song has verses 0-5"`
+	// Verse lines were requested from
+	Verse *int `json:"verse,omitempty" example:"2"`
+	First int  `json:"first" example:"0"`
+	Last  int  `json:"last" example:"5"`
+} // @name LyricsRangeResponse
+
+// Parses comma separated verse:line and verse:line-line references
+func parseLineRefs(value string) ([]domain.LineRef, error) {
+	var refs []domain.LineRef
+
+	for _, field := range splitFields(value) {
+		verse, lines, ok := strings.Cut(field, ":")
+		if !ok {
+			return nil, errors.Wrapf(domain.ErrLineRef, "%s", field)
+		}
+
+		from, to, isRange := strings.Cut(lines, "-")
+		if !isRange {
+			to = from
+		}
+
+		var (
+			ref                      domain.LineRef
+			verseErr, fromErr, toErr error
+		)
+
+		ref.Verse, verseErr = strconv.Atoi(verse)
+		ref.LineFrom, fromErr = strconv.Atoi(from)
+		ref.LineTo, toErr = strconv.Atoi(to)
+
+		if verseErr != nil || fromErr != nil || toErr != nil || ref.LineFrom > ref.LineTo {
+			return nil, errors.Wrapf(domain.ErrLineRef, "%s", field)
+		}
+
+		refs = append(refs, ref)
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,137 @@
+package app
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Sadere/song-depository/internal/domain"
+)
+
+func TestGetLyricsQuery(t *testing.T) {
+	one, three := 1, 3
+
+	tests := []struct {
+		target string
+		want   domain.LyricsQuery
+	}{
+		{
+			target: "/song/5/lyrics",
+			want:   domain.LyricsQuery{},
+		},
+		{
+			target: "/song/5/lyrics?verse_from=1&verse_to=3",
+			want:   domain.LyricsQuery{VerseFrom: &one, VerseTo: &three},
+		},
+		{
+			target: "/song/5/lyrics?page=2&limit=10",
+			want:   domain.LyricsQuery{Page: 2, Limit: 10},
+		},
+		{
+			target: "/song/5/lyrics?lines=0:1,%202:0-3",
+			want: domain.LyricsQuery{Lines: []domain.LineRef{
+				{Verse: 0, LineFrom: 1, LineTo: 1},
+				{Verse: 2, LineFrom: 0, LineTo: 3},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			var got domain.LyricsQuery
+
+			songService := &stubSongService{
+				lyrics: func(songID uint64, query domain.LyricsQuery) (*domain.LyricsPage, error) {
+					if songID != 5 {
+						t.Errorf("song id = %d, want 5", songID)
+					}
+
+					got = query
+
+					return &domain.LyricsPage{}, nil
+				},
+			}
+
+			w := serve(newTestServer(t, songService), http.MethodGet, tt.target, "", nil)
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, http.StatusOK, w.Body)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("query = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetLyricsErrors(t *testing.T) {
+	two := 2
+
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		wantStatus int
+		// Part of error message
+		wantError string
+	}{
+		{name: "invalid song id", target: "/song/x/lyrics", wantStatus: http.StatusBadRequest},
+		{name: "invalid verse", target: "/song/1/lyrics?verse_to=last", wantStatus: http.StatusBadRequest, wantError: "verse_to"},
+		{name: "both verses invalid", target: "/song/1/lyrics?verse_to=last&verse_from=first", wantStatus: http.StatusBadRequest, wantError: "verse_from"},
+		{name: "both page params invalid", target: "/song/1/lyrics?limit=all&page=-1", wantStatus: http.StatusBadRequest, wantError: "page"},
+		{name: "negative page", target: "/song/1/lyrics?page=-1", wantStatus: http.StatusBadRequest, wantError: "page"},
+		{name: "line without verse", target: "/song/1/lyrics?lines=3", wantStatus: http.StatusBadRequest, wantError: "3"},
+		{name: "reversed line range", target: "/song/1/lyrics?lines=0:1,1:4-2", wantStatus: http.StatusBadRequest, wantError: "1:4-2"},
+		{name: "line not a number", target: "/song/1/lyrics?lines=0:a", wantStatus: http.StatusBadRequest, wantError: "0:a"},
+		{name: "combined selectors", target: "/song/1/lyrics", err: domain.ErrLyricsQuery, wantStatus: http.StatusBadRequest},
+		{name: "song not found", target: "/song/1/lyrics", err: domain.ErrSongNotFound, wantStatus: http.StatusNotFound},
+		{
+			name:       "verse out of range",
+			target:     "/song/1/lyrics",
+			err:        &domain.RangeError{First: 0, Last: 5},
+			wantStatus: http.StatusNotFound,
+			wantError:  "song has verses 0-5",
+		},
+		{
+			name:       "line out of range",
+			target:     "/song/1/lyrics",
+			err:        &domain.RangeError{Verse: &two, First: 0, Last: 3},
+			wantStatus: http.StatusNotFound,
+			wantError:  "verse 2 has lines 0-3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			songService := &stubSongService{
+				lyrics: func(uint64, domain.LyricsQuery) (*domain.LyricsPage, error) {
+					return nil, tt.err
+				},
+			}
+
+			w := serve(newTestServer(t, songService), http.MethodGet, tt.target, "", nil)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d, body: %s", w.Code, tt.wantStatus, w.Body)
+			}
+
+			var body LyricsRangeResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode response: %v", err)
+			}
+
+			if !strings.Contains(body.Error, tt.wantError) {
+				t.Errorf("error = %q, want it to contain %q", body.Error, tt.wantError)
+			}
+
+			var rangeErr *domain.RangeError
+			if errors.As(tt.err, &rangeErr) && (body.First != rangeErr.First || body.Last != rangeErr.Last || !reflect.DeepEqual(body.Verse, rangeErr.Verse)) {
+				t.Errorf("range = %+v, want %+v", body, rangeErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// ListRevisions godoc
+//
+//	@Summary		List song revisions
+//	@Description	Lists revisions of song newest first, next page is fetched with next_cursor of previous response
+//	@Tags			revisions
+//	@Produce		json
+//	@Param			song_id	path		int		true	"Song ID"
+//	@Param			cursor	query		string	false	"Cursor of requested page"
+//	@Param			limit	query		int		false	"Number of revisions on page"
+//	@Success		200	{object}	domain.RevisionPage
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/revisions [get]
+func (s *Server) ListRevisions(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	limit, err := strconv.ParseUint(c.DefaultQuery("limit", "0"), 10, 32)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := s.songService.Revisions(c.Request.Context(), songID, c.Query("cursor"), uint(limit))
+
+	switch {
+	case errors.Is(err, domain.ErrSongNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrInvalidCursor), errors.Is(err, domain.ErrPageLimit):
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetRevision godoc
+//
+//	@Summary		Get song revision
+//	@Description	Gets song revision with song fields saved by it
+//	@Tags			revisions
+//	@Produce		json
+//	@Param			song_id		path		int	true	"Song ID"
+//	@Param			revision	path		int	true	"Revision number"
+//	@Success		200	{object}	model.SongRevision
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/revisions/{revision} [get]
+func (s *Server) GetRevision(c *gin.Context) {
+	songID, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	rev, err := s.songService.Revision(c.Request.Context(), songID, revision)
+
+	if errors.Is(err, domain.ErrRevisionNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rev)
+}
+
+// DiffRevisions godoc
+//
+//	@Summary		Diff song revisions
+//	@Description	Compares song revision with another one, previous revision by default.
+//	@Description	Lyrics are compared line by line as unified diff, other fields are listed with old and new values
+//	@Tags			revisions
+//	@Produce		json
+//	@Param			song_id		path		int	true	"Song ID"
+//	@Param			revision	path		int	true	"Revision number"
+//	@Param			from		query		int	false	"Revision to compare with, 0 for empty song"
+//	@Success		200	{object}	domain.RevisionDiff
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/revisions/{revision}/diff [get]
+func (s *Server) DiffRevisions(c *gin.Context) {
+	var from *uint64
+
+	songID, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	if f, ok := c.GetQuery("from"); ok {
+		n, err := strconv.ParseUint(f, 10, 64)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		from = &n
+	}
+
+	diff, err := s.songService.DiffRevisions(c.Request.Context(), songID, revision, from)
+
+	if errors.Is(err, domain.ErrRevisionNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// RestoreRevision godoc
+//
+//	@Summary		Restore song revision
+//	@Description	Sets song fields to ones saved by revision, restored song is saved as new revision.
+//	@Description	Restored lyrics, release date and link are marked as set by hand
+//	@Tags			revisions
+//	@Produce		json
+//	@Param			song_id			path		int		true	"Song ID"
+//	@Param			revision		path		int		true	"Revision number"
+//	@Param			If-Match		header		string	false	"ETag of current song version"
+//	@Param			X-Author		header		string	false	"Author of change saved with song revision"
+//	@Param			X-Change-Reason	header		string	false	"Reason of change saved with song revision"
+//	@Success		200	{object}	model.Song
+//	@Header			200	{string}	ETag	"New song entity tag"
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	DuplicateSongResponse
+//	@Failure		412	{object}	ErrorResponse
+//	@Failure		422	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/revisions/{revision}/restore [post]
+func (s *Server) RestoreRevision(c *gin.Context) {
+	songID, revision, ok := revisionParams(c)
+	if !ok {
+		return
+	}
+
+	version, err := ifMatchVersion(c.GetHeader("If-Match"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("restore song revision request: ", songID, " ", revision)
+
+	song, err := s.songService.RestoreRevision(c.Request.Context(), songID, revision, version, changeNote(c))
+
+	if abortDuplicateSong(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrSongNotFound), errors.Is(err, domain.ErrRevisionNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrVersionMismatch):
+		c.AbortWithStatusJSON(http.StatusPreconditionFailed, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrInvalidName),
+		errors.Is(err, domain.ErrCreditRole),
+		errors.Is(err, domain.ErrGenreNotFound),
+		errors.Is(err, domain.ErrInvalidTag):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Header("ETag", versionETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
+
+// Parses song ID and revision path parameters, responds with error if they are invalid
+func revisionParams(c *gin.Context) (uint64, uint64, bool) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, 0, false
+	}
+
+	revision, err := strconv.ParseUint(c.Param("revision"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return 0, 0, false
+	}
+
+	return songID, revision, true
+}
+
+// Reads author and reason of song change from request headers
+func changeNote(c *gin.Context) domain.ChangeNote {
+	return domain.ChangeNote{
+		Author: c.GetHeader("X-Author"),
+		Reason: c.GetHeader("X-Change-Reason"),
+	}
+}
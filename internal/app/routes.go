@@ -27,10 +27,60 @@ func (s *Server) setupRoutes() (*gin.Engine, error) {
 		r.POST("/list-songs", s.ListSongs)
 		r.POST("/song", s.AddSong)
 		r.PUT("/song/:id", s.ModifySong)
+		r.POST("/song/:id/refresh", s.RefreshSong)
+		r.POST("/songs/refresh", s.RefreshSongs)
+		r.POST("/songs/facets", s.SongFacets)
+		r.PUT("/artists/:id", s.RenameArtist)
+		r.POST("/artists/:id/aliases", s.MergeArtistAliases)
+		r.POST("/albums", s.AddAlbum)
+		r.PUT("/albums/:id", s.ModifyAlbum)
+		r.PUT("/albums/:id/tracks", s.SetAlbumTracks)
+		r.POST("/genres", s.AddGenre)
+		r.POST("/song/:id/merge", s.MergeSong)
 	}
 
 	r.GET("/song-text", s.GetSongText)
+	r.GET("/song/:id", s.GetSong)
+	r.GET("/song/:id/status", s.GetSongStatus)
+	r.GET("/song/:id/lyrics", s.GetLyrics)
+	r.GET("/song/:id/timed-lyrics", s.GetTimedLyrics)
+	r.GET("/song/:id/timed-lyrics/active", s.GetActiveLines)
+	r.DELETE("/song/:id/timed-lyrics", s.DeleteTimedLyrics)
+	r.GET("/song/:id/lrc", s.ExportLRC)
+	r.PUT("/song/:id/lrc", s.ImportLRC)
+	r.GET("/song/:id/chords", s.GetChordSheet)
+	r.PUT("/song/:id/chords", s.ImportChordPro)
+	r.DELETE("/song/:id/chords", s.DeleteChordSheet)
+	r.GET("/song/:id/revisions", s.ListRevisions)
+	r.GET("/song/:id/revisions/:revision", s.GetRevision)
+	r.GET("/song/:id/revisions/:revision/diff", s.DiffRevisions)
+	r.POST("/song/:id/revisions/:revision/restore", s.RestoreRevision)
+	r.PATCH("/song/:id", s.PatchSong)
 	r.DELETE("/song/:id", s.DeleteSong)
+	r.POST("/song/:id/restore", s.RestoreSong)
+	r.GET("/songs/trash", s.ListTrash)
+	r.GET("/songs/duplicates", s.ListDuplicates)
+
+	// Admin routes
+	admin := r.Group("/admin")
+
+	admin.Use(middleware.AdminToken(s.config.AdminToken))
+	{
+		admin.DELETE("/song/:id", s.HardDeleteSong)
+	}
+
+	r.GET("/artists", s.ListArtists)
+	r.GET("/artists/:id", s.GetArtist)
+	r.DELETE("/artists/:id", s.DeleteArtist)
+
+	r.GET("/albums", s.ListAlbums)
+	r.GET("/albums/:id", s.GetAlbum)
+	r.DELETE("/albums/:id", s.DeleteAlbum)
+
+	r.GET("/genres", s.ListGenres)
+	r.DELETE("/genres/:id", s.DeleteGenre)
+
+	r.GET("/status", s.Status)
 
 	// Swagger routes
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
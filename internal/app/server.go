@@ -1,22 +1,37 @@
 package app
 
 import (
+	"context"
+	"net"
 	"net/http"
 
 	"github.com/Sadere/song-depository/internal/config"
 	"github.com/Sadere/song-depository/internal/database"
+	"github.com/Sadere/song-depository/internal/metadata"
 	"github.com/Sadere/song-depository/internal/repository"
 	"github.com/Sadere/song-depository/internal/service"
+	"github.com/Sadere/song-depository/internal/worker"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
 type Server struct {
-	config      *config.Config
-	songService service.ISongService
-	log         *zap.SugaredLogger
-	db          *sqlx.DB
+	config        *config.Config
+	songService   service.ISongService
+	artistService service.IArtistService
+	albumService  service.IAlbumService
+	genreService  service.IGenreService
+	infoProvider  *metadata.HTTPProvider
+	enrichment    *worker.EnrichmentPool
+	purger        *worker.TrashPurger
+	log           *zap.SugaredLogger
+	db            *sqlx.DB
+	srv           *http.Server
+
+	// Parent context of all requests, cancelled on shutdown
+	baseCtx context.Context
+	cancel  context.CancelFunc
 }
 
 func NewServer(
@@ -24,17 +39,41 @@ func NewServer(
 	log *zap.SugaredLogger,
 	db *sqlx.DB,
 ) *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+
 	// Init repo
 	songRepo := repository.NewPgSongRepository(db)
+	enrichRepo := repository.NewPgEnrichmentRepository(db)
+	artistRepo := repository.NewPgArtistRepository(db)
+	albumRepo := repository.NewPgAlbumRepository(db)
+	genreRepo := repository.NewPgGenreRepository(db)
+
+	// Init song detail provider
+	provider := metadata.NewHTTPProvider(cfg, log)
 
 	// Init service
-	songService := service.NewSongService(cfg, songRepo, log)
+	songService := service.NewSongService(cfg, songRepo, enrichRepo, albumRepo, provider, log)
+	artistService := service.NewArtistService(cfg, artistRepo, log)
+	albumService := service.NewAlbumService(cfg, albumRepo, log)
+	genreService := service.NewGenreService(cfg, genreRepo, log)
+
+	// Init background workers
+	enrichmentPool := worker.NewEnrichmentPool(cfg, enrichRepo, provider, log)
+	purger := worker.NewTrashPurger(cfg, songRepo, log)
 
 	return &Server{
-		config:      cfg,
-		songService: songService,
-		log:         log,
-		db:          db,
+		config:        cfg,
+		songService:   songService,
+		artistService: artistService,
+		albumService:  albumService,
+		genreService:  genreService,
+		infoProvider:  provider,
+		enrichment:    enrichmentPool,
+		purger:        purger,
+		log:           log,
+		db:            db,
+		baseCtx:       ctx,
+		cancel:        cancel,
 	}
 }
 
@@ -51,17 +90,64 @@ func (s *Server) Start() error {
 		return errors.Wrap(err, "setupRoutes")
 	}
 
-	srv := &http.Server{
+	// Start enrichment of pending songs
+	s.enrichment.Start(s.baseCtx)
+
+	// Start removal of expired songs from trash
+	s.purger.Start(s.baseCtx)
+
+	s.srv = &http.Server{
 		Addr:    s.config.Address,
 		Handler: r,
+		BaseContext: func(net.Listener) context.Context {
+			return s.baseCtx
+		},
 	}
 
 	// Run server in background
 	go func() {
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		if err := s.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			s.log.Fatalf("listen: %s\n", err)
 		}
 	}()
 
 	return nil
 }
+
+// Gracefully stops server: stops accepting new connections, waits for active
+// requests until ctx is done, then releases DB connections and flushes logs
+func (s *Server) Shutdown(ctx context.Context) error {
+	var shutdownErr error
+
+	// Wait for active handlers
+	if s.srv != nil {
+		err := s.srv.Shutdown(ctx)
+		if err != nil {
+			shutdownErr = errors.Wrap(err, "srv.Shutdown")
+			s.log.Error("failed to drain active requests: ", err)
+		}
+	}
+
+	// Abort queries and music info requests which are still running
+	s.cancel()
+
+	// Wait for background workers to leave their jobs
+	s.enrichment.Wait()
+	s.purger.Wait()
+
+	err := s.db.Close()
+	if err != nil {
+		s.log.Error("failed to close db: ", err)
+
+		if shutdownErr == nil {
+			shutdownErr = errors.Wrap(err, "db.Close")
+		}
+	}
+
+	s.log.Info("server stopped")
+
+	// Flush buffered logs, stderr sync errors are ignored
+	_ = s.log.Sync()
+
+	return shutdownErr
+}
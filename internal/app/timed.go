@@ -0,0 +1,224 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// GetTimedLyrics godoc
+//
+//	@Summary		Get synchronized lyrics
+//	@Description	Gets lines of synchronized lyrics with start offsets and word timings in milliseconds
+//	@Tags			timed lyrics
+//	@Produce		json
+//	@Param			song_id	path		int	true	"Song ID"
+//	@Success		200	{array}		model.TimedLine
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/timed-lyrics [get]
+func (s *Server) GetTimedLyrics(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lines, err := s.songService.TimedLyrics(c.Request.Context(), songID)
+
+	if abortTimedError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, lines)
+}
+
+// GetActiveLines godoc
+//
+//	@Summary		Get active lyrics lines
+//	@Description	Gets lines of synchronized lyrics sung at playback position, word being sung and next line
+//	@Tags			timed lyrics
+//	@Produce		json
+//	@Param			song_id		path		int	true	"Song ID"
+//	@Param			position_ms	query		int	true	"Playback position in milliseconds"
+//	@Success		200	{object}	domain.ActiveLyrics
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/timed-lyrics/active [get]
+func (s *Server) GetActiveLines(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	position, err := strconv.ParseInt(c.Query("position_ms"), 10, 64)
+	if err != nil || position < 0 {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "position_ms must be non-negative number of milliseconds"})
+		return
+	}
+
+	active, err := s.songService.ActiveLines(c.Request.Context(), songID, position)
+
+	if abortTimedError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, active)
+}
+
+// ImportLRC godoc
+//
+//	@Summary		Upload LRC lyrics
+//	@Description	Replaces synchronized lyrics of song with LRC or enhanced LRC document.
+//	@Description	Timestamps must not go backward, except repeats of line with several timestamps which are placed by their timestamps. [offset:] tag is applied to all of them
+//	@Tags			timed lyrics
+//	@Accept			plain
+//	@Produce		json
+//	@Param			song_id	path		int		true	"Song ID"
+//	@Param			message	body		string	true	"LRC document"
+//	@Success		200	{array}		model.TimedLine
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		422	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/lrc [put]
+func (s *Server) ImportLRC(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lrc, err := c.GetRawData()
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("import lrc request: ", songID, " ", len(lrc), " bytes")
+
+	lines, err := s.songService.ImportLRC(c.Request.Context(), songID, string(lrc))
+
+	if abortTimedError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, lines)
+}
+
+// ExportLRC godoc
+//
+//	@Summary		Download LRC lyrics
+//	@Description	Formats synchronized lyrics of song as LRC, enhanced LRC keeps word timings
+//	@Tags			timed lyrics
+//	@Produce		plain
+//	@Param			song_id		path		int		true	"Song ID"
+//	@Param			enhanced	query		bool	false	"Export enhanced LRC"
+//	@Success		200	{string}	string	"LRC document"
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/lrc [get]
+func (s *Server) ExportLRC(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	enhanced, err := strconv.ParseBool(c.DefaultQuery("enhanced", "false"))
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	lrc, err := s.songService.ExportLRC(c.Request.Context(), songID, enhanced)
+
+	if abortTimedError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	// Overrides JSON content type set by middleware
+	c.Header("Content-Type", "text/plain; charset=utf-8")
+	c.String(http.StatusOK, lrc)
+}
+
+// DeleteTimedLyrics godoc
+//
+//	@Summary		Delete synchronized lyrics
+//	@Description	Removes synchronized lyrics of song, plain lyrics are kept
+//	@Tags			timed lyrics
+//	@Produce		json
+//	@Param			song_id	path		int	true	"Song ID"
+//	@Success		200
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/timed-lyrics [delete]
+func (s *Server) DeleteTimedLyrics(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("request to delete timed lyrics of song id: ", songID)
+
+	err = s.songService.RemoveTimedLyrics(c.Request.Context(), songID)
+
+	if abortTimedError(c, err) {
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// Responds with error matching synchronized lyrics failure, returns false for other errors
+func abortTimedError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, domain.ErrSongNotFound), errors.Is(err, domain.ErrNoTimedLyrics):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, domain.ErrInvalidLRC), errors.Is(err, domain.ErrTimestampOrder):
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		return false
+	}
+
+	return true
+}
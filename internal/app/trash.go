@@ -0,0 +1,134 @@
+package app
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// ListTrash godoc
+//
+//	@Summary		List deleted songs
+//	@Description	Lists songs in trash, they are purged after retention period. Next page is fetched with next_cursor of previous response
+//	@Tags			trash
+//	@Produce		json
+//	@Param			cursor	query		string	false	"Cursor of requested page"
+//	@Param			limit	query		int		false	"Number of songs on page"
+//	@Success		200	{object}	domain.SongPage
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/songs/trash [get]
+func (s *Server) ListTrash(c *gin.Context) {
+	limit, err := strconv.ParseUint(c.DefaultQuery("limit", "0"), 10, 32)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	page, err := s.songService.Trash(c.Request.Context(), domain.ListOptions{
+		Cursor: c.Query("cursor"),
+		Limit:  uint(limit),
+	})
+
+	if isPageError(err) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// RestoreSong godoc
+//
+//	@Summary		Restore deleted song
+//	@Description	Takes song out of trash, song added again with same artist and name conflicts with it
+//	@Tags			trash
+//	@Produce		json
+//	@Param			song_id	path		int	true	"Song ID"
+//	@Success		200	{object}	model.Song
+//	@Header			200	{string}	ETag	"Song entity tag"
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		409	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/song/{song_id}/restore [post]
+func (s *Server) RestoreSong(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Debug("request to restore song id: ", songID)
+
+	song, err := s.songService.Restore(c.Request.Context(), songID)
+
+	if abortDuplicateSong(c, err) {
+		return
+	}
+
+	switch {
+	case errors.Is(err, domain.ErrSongNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, domain.ErrSongNotDeleted):
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Header("ETag", versionETag(song.Version))
+	c.JSON(http.StatusOK, song)
+}
+
+// HardDeleteSong godoc
+//
+//	@Summary		Delete song permanently
+//	@Description	Removes song with its lyrics, revisions and relations right away, whether it's in trash or not. Requires admin token
+//	@Tags			trash
+//	@Produce		json
+//	@Param			song_id			path		int		true	"Song ID"
+//	@Param			X-Admin-Token	header		string	true	"Admin token"
+//	@Success		200
+//	@Failure		400	{object}	ErrorResponse
+//	@Failure		401	{object}	ErrorResponse
+//	@Failure		403	{object}	ErrorResponse
+//	@Failure		404	{object}	ErrorResponse
+//	@Failure		500	{object}	ErrorResponse
+//	@Router			/admin/song/{song_id} [delete]
+func (s *Server) HardDeleteSong(c *gin.Context) {
+	songID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	s.log.Info("request to permanently delete song id: ", songID)
+
+	err = s.songService.HardDelete(c.Request.Context(), songID)
+
+	if errors.Is(err, domain.ErrSongNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err != nil {
+		s.log.Error(err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "unexpected error"})
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
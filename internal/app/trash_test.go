@@ -0,0 +1,204 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+// Keeps songs in memory, deleted ones are moved to trash
+type memoryTrash struct {
+	live    map[uint64]*model.Song
+	trashed map[uint64]*model.Song
+}
+
+func newMemoryTrash(songs ...*model.Song) (*memoryTrash, *stubSongService) {
+	m := &memoryTrash{
+		live:    make(map[uint64]*model.Song),
+		trashed: make(map[uint64]*model.Song),
+	}
+
+	for _, song := range songs {
+		m.live[song.ID] = song
+	}
+
+	return m, &stubSongService{
+		remove: func(songID, _ uint64) error {
+			song, ok := m.live[songID]
+			if !ok {
+				return domain.ErrSongNotFound
+			}
+
+			delete(m.live, songID)
+			m.trashed[songID] = song
+
+			return nil
+		},
+		trash: func(opts domain.ListOptions) (*domain.SongPage, error) {
+			if len(opts.Cursor) > 0 {
+				return nil, domain.ErrInvalidCursor
+			}
+
+			page := &domain.SongPage{Songs: model.Songs{}, Limit: opts.Limit}
+			for _, song := range m.trashed {
+				page.Songs = append(page.Songs, song)
+			}
+
+			return page, nil
+		},
+		restore: func(songID uint64) (*model.Song, error) {
+			if _, ok := m.live[songID]; ok {
+				return nil, domain.ErrSongNotDeleted
+			}
+
+			song, ok := m.trashed[songID]
+			if !ok {
+				return nil, domain.ErrSongNotFound
+			}
+
+			delete(m.trashed, songID)
+			song.Version++
+			m.live[songID] = song
+
+			return song, nil
+		},
+		hardDelete: func(songID uint64) error {
+			_, live := m.live[songID]
+			_, trashed := m.trashed[songID]
+
+			if !live && !trashed {
+				return domain.ErrSongNotFound
+			}
+
+			delete(m.live, songID)
+			delete(m.trashed, songID)
+
+			return nil
+		},
+	}
+}
+
+// Returns IDs of songs listed in trash
+func trashIDs(t *testing.T, w *httptest.ResponseRecorder) []uint64 {
+	t.Helper()
+
+	var page struct {
+		Songs []struct {
+			ID uint64 `json:"id"`
+		} `json:"songs"`
+	}
+
+	if err := json.Unmarshal(w.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decode trash page: %v", err)
+	}
+
+	ids := make([]uint64, len(page.Songs))
+	for i, song := range page.Songs {
+		ids[i] = song.ID
+	}
+
+	return ids
+}
+
+func TestTrashAndRestore(t *testing.T) {
+	m, songService := newMemoryTrash(&model.Song{ID: 1, Version: 3, Name: "Uprising", Group: "Muse", ManualFields: model.FieldSet{}})
+	r := newTestServer(t, songService)
+
+	expect := func(w *httptest.ResponseRecorder, status int) {
+		t.Helper()
+
+		if w.Code != status {
+			t.Fatalf("status = %d, want %d, body: %s", w.Code, status, w.Body)
+		}
+	}
+
+	// Nothing to restore yet
+	expect(serve(r, http.MethodPost, "/song/1/restore", "", nil), http.StatusConflict)
+	expect(serve(r, http.MethodPost, "/song/2/restore", "", nil), http.StatusNotFound)
+
+	w := serve(r, http.MethodGet, "/songs/trash", "", nil)
+	expect(w, http.StatusOK)
+
+	if ids := trashIDs(t, w); len(ids) != 0 {
+		t.Fatalf("trash before delete = %v, want empty", ids)
+	}
+
+	expect(serve(r, http.MethodDelete, "/song/1", "", nil), http.StatusOK)
+
+	w = serve(r, http.MethodGet, "/songs/trash?limit=5", "", nil)
+	expect(w, http.StatusOK)
+
+	if ids := trashIDs(t, w); len(ids) != 1 || ids[0] != 1 {
+		t.Fatalf("trash after delete = %v, want [1]", ids)
+	}
+
+	// Deleted song is gone for other requests
+	expect(serve(r, http.MethodDelete, "/song/1", "", nil), http.StatusNotFound)
+
+	w = serve(r, http.MethodPost, "/song/1/restore", "", nil)
+	expect(w, http.StatusOK)
+
+	if got := w.Header().Get("ETag"); got != `"4"` {
+		t.Errorf("restored song ETag = %q, want %q", got, `"4"`)
+	}
+
+	var restored model.Song
+	if err := json.Unmarshal(w.Body.Bytes(), &restored); err != nil || restored.ID != 1 {
+		t.Errorf("restored song = %+v, error %v", restored, err)
+	}
+
+	if _, ok := m.live[1]; !ok {
+		t.Error("restored song is not live")
+	}
+
+	expect(serve(r, http.MethodPost, "/song/1/restore", "", nil), http.StatusConflict)
+}
+
+func TestListTrashErrors(t *testing.T) {
+	_, songService := newMemoryTrash()
+	r := newTestServer(t, songService)
+
+	for _, target := range []string{"/songs/trash?limit=-1", "/songs/trash?limit=many", "/songs/trash?cursor=stale"} {
+		if w := serve(r, http.MethodGet, target, "", nil); w.Code != http.StatusBadRequest {
+			t.Errorf("GET %s: status = %d, want %d", target, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestHardDeleteSong(t *testing.T) {
+	m, songService := newMemoryTrash(&model.Song{ID: 1}, &model.Song{ID: 2})
+	r := newTestServer(t, songService)
+
+	tests := []struct {
+		name       string
+		target     string
+		token      string
+		wantStatus int
+	}{
+		{name: "no token", target: "/admin/song/1", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", target: "/admin/song/1", token: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "live song", target: "/admin/song/1", token: testAdminToken, wantStatus: http.StatusOK},
+		{name: "already deleted", target: "/admin/song/1", token: testAdminToken, wantStatus: http.StatusNotFound},
+		{name: "invalid id", target: "/admin/song/one", token: testAdminToken, wantStatus: http.StatusBadRequest},
+	}
+
+	// Runs in order, later cases depend on earlier ones
+	for _, tt := range tests {
+		header := http.Header{}
+		if len(tt.token) > 0 {
+			header.Set("X-Admin-Token", tt.token)
+		}
+
+		if w := serve(r, http.MethodDelete, tt.target, "", header); w.Code != tt.wantStatus {
+			t.Errorf("%s: status = %d, want %d, body: %s", tt.name, w.Code, tt.wantStatus, w.Body)
+		}
+	}
+
+	if _, ok := m.live[2]; !ok {
+		t.Error("other song was deleted")
+	}
+}
@@ -0,0 +1,159 @@
+package chordpro
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Spelling preferences of transposed notes, empty one keeps spelling of original notes
+const (
+	Sharp = "sharp"
+	Flat  = "flat"
+)
+
+// Chord such as C, F#m7, Bbsus4 or D/F#
+var chordPattern = regexp.MustCompile(`^([A-G])([#b]?)([A-Za-z0-9+#()°øΔ^-]*)(?:/([A-G])([#b]?))?$`)
+
+// Chord placeholders which are never transposed
+var noChords = []string{"N.C.", "NC", "x"}
+
+var (
+	naturals   = map[byte]int{'C': 0, 'D': 2, 'E': 4, 'F': 5, 'G': 7, 'A': 9, 'B': 11}
+	sharpNotes = []string{"C", "C#", "D", "D#", "E", "F", "F#", "G", "G#", "A", "A#", "B"}
+	flatNotes  = []string{"C", "Db", "D", "Eb", "E", "F", "Gb", "G", "Ab", "A", "Bb", "B"}
+)
+
+// Roots of keys written with flats
+var (
+	flatMajorKeys = []int{1, 3, 5, 8, 10}
+	flatMinorKeys = []int{0, 2, 3, 5, 7, 10}
+)
+
+type chord struct {
+	root    note
+	suffix  string
+	bass    *note
+	noChord string
+}
+
+type note struct {
+	pitch int
+	flat  bool
+}
+
+func (n note) spell(accidentals string) string {
+	flat := n.flat
+
+	switch accidentals {
+	case Sharp:
+		flat = false
+	case Flat:
+		flat = true
+	}
+
+	if flat {
+		return flatNotes[n.pitch]
+	}
+
+	return sharpNotes[n.pitch]
+}
+
+func (n note) transpose(semitones int) note {
+	n.pitch = ((n.pitch+semitones)%12 + 12) % 12
+	return n
+}
+
+// Parses chord name, returns false when it isn't valid
+func parseChord(name string) (chord, bool) {
+	if slices.Contains(noChords, name) {
+		return chord{noChord: name}, true
+	}
+
+	m := chordPattern.FindStringSubmatch(name)
+	if m == nil {
+		return chord{}, false
+	}
+
+	c := chord{
+		root:   parseNote(m[1], m[2]),
+		suffix: m[3],
+	}
+
+	if len(m[4]) > 0 {
+		bass := parseNote(m[4], m[5])
+		c.bass = &bass
+	}
+
+	return c, true
+}
+
+func parseNote(letter, accidental string) note {
+	n := note{pitch: naturals[letter[0]]}
+
+	switch accidental {
+	case "#":
+		n = n.transpose(1)
+	case "b":
+		n = n.transpose(-1)
+		n.flat = true
+	}
+
+	return n
+}
+
+func (c chord) minor() bool {
+	return strings.HasPrefix(c.suffix, "m") && !strings.HasPrefix(c.suffix, "maj")
+}
+
+func (c chord) transpose(semitones int) chord {
+	if len(c.noChord) > 0 {
+		return c
+	}
+
+	c.root = c.root.transpose(semitones)
+
+	if c.bass != nil {
+		bass := c.bass.transpose(semitones)
+		c.bass = &bass
+	}
+
+	return c
+}
+
+func (c chord) spell(accidentals string) string {
+	if len(c.noChord) > 0 {
+		return c.noChord
+	}
+
+	name := c.root.spell(accidentals) + c.suffix
+
+	if c.bass != nil {
+		name += "/" + c.bass.spell(accidentals)
+	}
+
+	return name
+}
+
+// Returns spelling conventional for key
+func keyAccidentals(key chord) string {
+	flatKeys := flatMajorKeys
+	if key.minor() {
+		flatKeys = flatMinorKeys
+	}
+
+	// F# and Gb major are both common, original spelling is kept
+	if !key.minor() && key.root.pitch == 6 {
+		if key.root.flat {
+			return Flat
+		}
+
+		return Sharp
+	}
+
+	if slices.Contains(flatKeys, key.root.pitch) {
+		return Flat
+	}
+
+	return Sharp
+}
@@ -0,0 +1,410 @@
+package chordpro
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+const testSheet = `# Hymn
+{t: Amazing Grace}
+{st: Traditional}
+{key: G}
+{capo: 2}
+{artist: John Newton}
+{x_source: hymnal}
+{c: Slowly}
+[G]Amazing [G7]grace how [C]sweet the [G]sound
+
+That saved a wretch like me
+
+
+{soc: Refrain}
+[C]I once was [G]lost
+{eoc}
+{chorus}
+{start_of_tab}
+e|--0--[x]--|
+{end_of_tab}
+`
+
+func TestParse(t *testing.T) {
+	want := &model.ChordSheet{
+		Title:    "Amazing Grace",
+		Subtitle: "Traditional",
+		Key:      "G",
+		Capo:     2,
+		Meta:     map[string]string{"artist": "John Newton", "x_source": "hymnal"},
+		Sections: []model.ChordSection{
+			{
+				Type: model.ChordVerse,
+				Lines: []model.ChordLine{
+					{Comment: "Slowly"},
+					{Segments: []model.ChordSegment{
+						{Chord: "G", Lyrics: "Amazing "},
+						{Chord: "G7", Lyrics: "grace how "},
+						{Chord: "C", Lyrics: "sweet the "},
+						{Chord: "G", Lyrics: "sound"},
+					}},
+				},
+			},
+			{
+				Type: model.ChordVerse,
+				Lines: []model.ChordLine{
+					{Segments: []model.ChordSegment{{Lyrics: "That saved a wretch like me"}}},
+				},
+			},
+			{
+				Type:  model.ChordChorus,
+				Label: "Refrain",
+				Lines: []model.ChordLine{
+					{Segments: []model.ChordSegment{{Chord: "C", Lyrics: "I once was "}, {Chord: "G", Lyrics: "lost"}}},
+				},
+			},
+			{Type: model.ChordChorus, Label: "Chorus", Repeat: true},
+			{
+				Type:  model.ChordTab,
+				Label: "Tab",
+				Lines: []model.ChordLine{
+					{Segments: []model.ChordSegment{{Lyrics: "e|--0--[x]--|"}}},
+				},
+			},
+		},
+	}
+
+	got, err := Parse(testSheet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Parse() =\n%s\nwant\n%s", formatSheet(got), formatSheet(want))
+	}
+}
+
+func TestParseChords(t *testing.T) {
+	tests := []struct {
+		name  string
+		line  string
+		want  []model.ChordSegment
+		valid bool
+	}{
+		{name: "plain lyrics", line: "just words", want: []model.ChordSegment{{Lyrics: "just words"}}, valid: true},
+		{name: "lyrics before chord", line: "Oh [Am]yes", want: []model.ChordSegment{{Lyrics: "Oh "}, {Chord: "Am", Lyrics: "yes"}}, valid: true},
+		{name: "chords without lyrics", line: "[C][G]", want: []model.ChordSegment{{Chord: "C"}, {Chord: "G"}}, valid: true},
+		{name: "slash chord", line: "[D/F#]a", want: []model.ChordSegment{{Chord: "D/F#", Lyrics: "a"}}, valid: true},
+		{name: "extended chords", line: "[Cmaj7][F#m7b5][Bbsus4][Gadd9][E7(#9)][Bdim][C+]", valid: true},
+		{name: "no chord", line: "[N.C.]stop [NC]now", valid: true},
+		{name: "lowercase root", line: "[am]a"},
+		{name: "unknown root", line: "[H]a"},
+		{name: "invalid bass", line: "[C/X]a"},
+		{name: "empty chord", line: "[]a"},
+		{name: "unclosed chord", line: "[C a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sheet, err := Parse(tt.line)
+
+			if !tt.valid {
+				if !errors.Is(err, domain.ErrInvalidChordPro) {
+					t.Fatalf("error = %v, want %v", err, domain.ErrInvalidChordPro)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.want == nil {
+				return
+			}
+
+			got := sheet.Sections[0].Lines[0].Segments
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("segments = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+	}{
+		{name: "unclosed directive", source: "{title: Song"},
+		{name: "unknown directive", source: "{colour: red}"},
+		{name: "unknown section", source: "{start_of_grid}\n{end_of_grid}"},
+		{name: "invalid key", source: "{key: H}"},
+		{name: "no chord key", source: "{key: N.C.}"},
+		{name: "capo isn't number", source: "{capo: two}"},
+		{name: "negative capo", source: "{capo: -1}"},
+		{name: "capo too high", source: "{capo: 25}"},
+		{name: "unclosed section", source: "{soc}\n[C]la"},
+		{name: "end without start", source: "[C]la\n{eoc}"},
+		{name: "mismatched end", source: "{sov}\nla\n{eoc}"},
+		{name: "nested section", source: "{sov}\n{soc}\n{eoc}\n{eov}"},
+		{name: "chorus repeated inside section", source: "{sov}\n{chorus}\n{eov}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.source)
+
+			if !errors.Is(err, domain.ErrInvalidChordPro) {
+				t.Fatalf("error = %v, want %v", err, domain.ErrInvalidChordPro)
+			}
+		})
+	}
+}
+
+func TestTranspose(t *testing.T) {
+	tests := []struct {
+		name        string
+		key         string
+		chords      string
+		semitones   int
+		accidentals string
+		wantKey     string
+		want        []string
+	}{
+		{
+			name:      "up to sharp key",
+			key:       "G",
+			chords:    "[G][D/F#][Em][C]",
+			semitones: 2,
+			wantKey:   "A",
+			want:      []string{"A", "E/G#", "F#m", "D"},
+		},
+		{
+			name:      "up to flat key",
+			key:       "C",
+			chords:    "[C][G/B][Am][F]",
+			semitones: 3,
+			wantKey:   "Eb",
+			want:      []string{"Eb", "Bb/D", "Cm", "Ab"},
+		},
+		{
+			name:      "down wraps below C",
+			key:       "C",
+			chords:    "[C][F][G7]",
+			semitones: -1,
+			wantKey:   "B",
+			want:      []string{"B", "E", "F#7"},
+		},
+		{
+			name:      "more than octave",
+			key:       "C",
+			chords:    "[C][F][G7]",
+			semitones: -13,
+			wantKey:   "B",
+			want:      []string{"B", "E", "F#7"},
+		},
+		{
+			name:      "octave keeps chords",
+			key:       "Bb",
+			chords:    "[Bb][Eb][F]",
+			semitones: 12,
+			wantKey:   "Bb",
+			want:      []string{"Bb", "Eb", "F"},
+		},
+		{
+			name:      "minor key to sharp key",
+			key:       "Am",
+			chords:    "[Am][E7][Dm]",
+			semitones: 2,
+			wantKey:   "Bm",
+			want:      []string{"Bm", "F#7", "Em"},
+		},
+		{
+			name:      "minor key to flat key",
+			key:       "Em",
+			chords:    "[Em][B7][C]",
+			semitones: -2,
+			wantKey:   "Dm",
+			want:      []string{"Dm", "A7", "Bb"},
+		},
+		{
+			name:      "major seventh key isn't minor",
+			key:       "Cmaj7",
+			chords:    "[Cmaj7/E][Dm7]",
+			semitones: 2,
+			wantKey:   "Dmaj7",
+			want:      []string{"Dmaj7/F#", "Em7"},
+		},
+		{
+			name:      "flat spelling of F sharp major is kept",
+			key:       "Db",
+			chords:    "[Db][Ab][Bbm]",
+			semitones: 5,
+			wantKey:   "Gb",
+			want:      []string{"Gb", "Db", "Ebm"},
+		},
+		{
+			name:      "sharp spelling of F sharp major is kept",
+			key:       "C#",
+			chords:    "[C#][G#][A#m]",
+			semitones: 5,
+			wantKey:   "F#",
+			want:      []string{"F#", "C#", "D#m"},
+		},
+		{
+			name:      "without key original spelling is kept",
+			chords:    "[Bb][D#][Db/F]",
+			semitones: 3,
+			want:      []string{"Db", "F#", "E/G#"},
+		},
+		{
+			name:      "enharmonic spellings are normalized",
+			chords:    "[E#][Cb][B#][Fb]",
+			semitones: 0,
+			want:      []string{"F", "B", "C", "E"},
+		},
+		{
+			name:        "sharps requested",
+			key:         "C",
+			chords:      "[C][Am][G]",
+			semitones:   1,
+			accidentals: Sharp,
+			wantKey:     "C#",
+			want:        []string{"C#", "A#m", "G#"},
+		},
+		{
+			name:        "flats requested",
+			key:         "G",
+			chords:      "[G][D/F#][Em]",
+			semitones:   1,
+			accidentals: Flat,
+			wantKey:     "Ab",
+			want:        []string{"Ab", "Eb/G", "Fm"},
+		},
+		{
+			name:      "no chord is kept",
+			key:       "C",
+			chords:    "[C][N.C.][x]",
+			semitones: 2,
+			wantKey:   "D",
+			want:      []string{"D", "N.C.", "x"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := tt.chords
+			if len(tt.key) > 0 {
+				source = "{key: " + tt.key + "}\n" + source
+			}
+
+			sheet, err := Parse(source)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			Transpose(sheet, tt.semitones, tt.accidentals)
+
+			if sheet.Key != tt.wantKey {
+				t.Errorf("key = %q, want %q", sheet.Key, tt.wantKey)
+			}
+
+			var got []string
+			for _, segment := range sheet.Sections[0].Lines[0].Segments {
+				got = append(got, segment.Chord)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chords = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransposeSkipsTabs(t *testing.T) {
+	sheet, err := Parse("{key: C}\n[C]la\n{sot}\n[C]e|--0--|\n{eot}")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	Transpose(sheet, 2, "")
+
+	if chord := sheet.Sections[0].Lines[0].Segments[0].Chord; chord != "D" {
+		t.Errorf("chord = %q, want %q", chord, "D")
+	}
+
+	if tab := sheet.Sections[1].Lines[0].Segments[0].Lyrics; tab != "[C]e|--0--|" {
+		t.Errorf("tab = %q, want %q", tab, "[C]e|--0--|")
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	sheet, err := Parse(testSheet)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	want := "Amazing Grace\n" +
+		"Traditional\n" +
+		"Key: G  Capo: 2\n" +
+		"\n" +
+		"(Slowly)\n" +
+		"G       G7        C         G\n" +
+		"Amazing grace how sweet the sound\n" +
+		"\n" +
+		"That saved a wretch like me\n" +
+		"\n" +
+		"[Refrain]\n" +
+		"C          G\n" +
+		"I once was lost\n" +
+		"\n" +
+		"[Chorus]\n" +
+		"\n" +
+		"[Tab]\n" +
+		"e|--0--[x]--|\n"
+
+	if got := RenderText(sheet); got != want {
+		t.Errorf("RenderText() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAlignLine(t *testing.T) {
+	tests := []struct {
+		name       string
+		line       string
+		wantChords string
+		wantLyrics string
+	}{
+		{name: "lyrics only", line: "la la", wantChords: "", wantLyrics: "la la"},
+		{name: "chords only", line: "[C] [G]", wantChords: "C G", wantLyrics: ""},
+		{name: "lyrics before first chord", line: "Oh [Am]yes", wantChords: "   Am", wantLyrics: "Oh yes"},
+		{name: "chord longer than lyrics", line: "[Am7]a[G]b", wantChords: "Am7 G", wantLyrics: "a   b"},
+		{name: "trailing chord", line: "[C]Hi [G]", wantChords: "C  G", wantLyrics: "Hi"},
+		{name: "multibyte lyrics", line: "[D]Привет [A]мир", wantChords: "D      A", wantLyrics: "Привет мир"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segments, err := parseSegments(tt.line)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			chords, lyrics := alignLine(segments)
+
+			if chords != tt.wantChords || lyrics != tt.wantLyrics {
+				t.Errorf("alignLine() = %q / %q, want %q / %q", chords, lyrics, tt.wantChords, tt.wantLyrics)
+			}
+		})
+	}
+}
+
+func formatSheet(sheet *model.ChordSheet) string {
+	data, _ := json.MarshalIndent(sheet, "", "  ")
+
+	return string(data)
+}
@@ -0,0 +1,273 @@
+// Parses, transposes and renders ChordPro chord sheets
+package chordpro
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/pkg/errors"
+)
+
+// Highest capo fret accepted
+const maxCapo = 24
+
+var lineBreaks = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// Short forms of directives
+var directiveAliases = map[string]string{
+	"t":   "title",
+	"st":  "subtitle",
+	"c":   "comment",
+	"ci":  "comment_italic",
+	"cb":  "comment_box",
+	"soc": "start_of_chorus",
+	"eoc": "end_of_chorus",
+	"sov": "start_of_verse",
+	"eov": "end_of_verse",
+	"sob": "start_of_bridge",
+	"eob": "end_of_bridge",
+	"sot": "start_of_tab",
+	"eot": "end_of_tab",
+}
+
+// Directives kept as sheet metadata
+var metaDirectives = []string{
+	"artist", "composer", "lyricist", "arranger", "album", "year", "copyright", "tempo", "time", "duration",
+}
+
+// Sections opened by start_of_ directives and their default labels
+var environments = map[string]model.ChordSection{
+	"verse":  {Type: model.ChordVerse},
+	"chorus": {Type: model.ChordChorus, Label: "Chorus"},
+	"bridge": {Type: model.ChordBridge, Label: "Bridge"},
+	"tab":    {Type: model.ChordTab, Label: "Tab"},
+}
+
+type parser struct {
+	sheet   *model.ChordSheet
+	section *model.ChordSection
+	// Section was opened by directive and must be closed by one
+	explicit bool
+}
+
+// Parses ChordPro document. Lines outside of section directives are split into verses by blank lines.
+// Chords, key and capo are validated, unknown directives are rejected except custom x_ ones
+func Parse(source string) (*model.ChordSheet, error) {
+	p := &parser{
+		sheet: &model.ChordSheet{
+			Sections: make([]model.ChordSection, 0),
+		},
+	}
+
+	for n, line := range strings.Split(lineBreaks.Replace(source), "\n") {
+		err := p.parseLine(strings.TrimRight(line, " \t"))
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", n+1)
+		}
+	}
+
+	if p.explicit {
+		return nil, errors.Wrapf(domain.ErrInvalidChordPro, "%s section isn't closed", p.section.Type)
+	}
+
+	p.close()
+
+	return p.sheet, nil
+}
+
+func (p *parser) parseLine(line string) error {
+	trimmed := strings.TrimSpace(line)
+
+	switch {
+	case strings.HasPrefix(trimmed, "#"):
+		return nil
+	case strings.HasPrefix(trimmed, "{"):
+		if !strings.HasSuffix(trimmed, "}") {
+			return errors.Wrap(domain.ErrInvalidChordPro, "directive isn't closed")
+		}
+
+		return p.parseDirective(trimmed[1 : len(trimmed)-1])
+	case len(trimmed) == 0:
+		// Blank lines only separate implicit verses
+		if !p.explicit {
+			p.close()
+		}
+
+		return nil
+	}
+
+	if p.section == nil {
+		p.open(model.ChordSection{Type: model.ChordVerse})
+	}
+
+	// Tabs are kept verbatim
+	if p.section.Type == model.ChordTab {
+		p.section.Lines = append(p.section.Lines, model.ChordLine{
+			Segments: []model.ChordSegment{{Lyrics: line}},
+		})
+
+		return nil
+	}
+
+	segments, err := parseSegments(line)
+	if err != nil {
+		return err
+	}
+
+	p.section.Lines = append(p.section.Lines, model.ChordLine{Segments: segments})
+
+	return nil
+}
+
+func (p *parser) parseDirective(directive string) error {
+	name, value, _ := strings.Cut(directive, ":")
+	name = strings.ToLower(strings.TrimSpace(name))
+	value = strings.TrimSpace(value)
+
+	if full, ok := directiveAliases[name]; ok {
+		name = full
+	}
+
+	switch {
+	case name == "title":
+		p.sheet.Title = value
+	case name == "subtitle":
+		p.sheet.Subtitle = value
+	case name == "key":
+		if key, ok := parseChord(value); !ok || len(key.noChord) > 0 {
+			return errors.Wrapf(domain.ErrInvalidChordPro, "invalid key %q", value)
+		}
+
+		p.sheet.Key = value
+	case name == "capo":
+		capo, err := strconv.Atoi(value)
+		if err != nil || capo < 0 || capo > maxCapo {
+			return errors.Wrapf(domain.ErrInvalidChordPro, "capo must be fret from 0 to %d", maxCapo)
+		}
+
+		p.sheet.Capo = capo
+	case name == "comment" || name == "comment_italic" || name == "comment_box":
+		if p.section == nil {
+			p.open(model.ChordSection{Type: model.ChordVerse})
+		}
+
+		p.section.Lines = append(p.section.Lines, model.ChordLine{Comment: value})
+	case name == "chorus":
+		if p.explicit {
+			return errors.Wrap(domain.ErrInvalidChordPro, "chorus can't be repeated inside section")
+		}
+
+		p.close()
+
+		label := value
+		if len(label) == 0 {
+			label = environments["chorus"].Label
+		}
+
+		p.sheet.Sections = append(p.sheet.Sections, model.ChordSection{
+			Type:   model.ChordChorus,
+			Label:  label,
+			Repeat: true,
+		})
+	case strings.HasPrefix(name, "start_of_"):
+		section, ok := environments[strings.TrimPrefix(name, "start_of_")]
+		if !ok {
+			return errors.Wrapf(domain.ErrInvalidChordPro, "unknown directive %s", name)
+		}
+
+		if p.explicit {
+			return errors.Wrapf(domain.ErrInvalidChordPro, "%s section isn't closed", p.section.Type)
+		}
+
+		p.close()
+
+		if len(value) > 0 {
+			section.Label = value
+		}
+
+		p.open(section)
+		p.explicit = true
+	case strings.HasPrefix(name, "end_of_"):
+		section, ok := environments[strings.TrimPrefix(name, "end_of_")]
+		if !ok {
+			return errors.Wrapf(domain.ErrInvalidChordPro, "unknown directive %s", name)
+		}
+
+		if !p.explicit || p.section.Type != section.Type {
+			return errors.Wrapf(domain.ErrInvalidChordPro, "%s without start_of_%s", name, section.Type)
+		}
+
+		p.close()
+	case isMeta(name):
+		if p.sheet.Meta == nil {
+			p.sheet.Meta = make(map[string]string)
+		}
+
+		p.sheet.Meta[name] = value
+	default:
+		return errors.Wrapf(domain.ErrInvalidChordPro, "unknown directive %s", name)
+	}
+
+	return nil
+}
+
+func (p *parser) open(section model.ChordSection) {
+	p.section = &section
+}
+
+// Adds current section to sheet, implicit sections without lines are dropped
+func (p *parser) close() {
+	if p.section != nil && (p.explicit || len(p.section.Lines) > 0) {
+		p.sheet.Sections = append(p.sheet.Sections, *p.section)
+	}
+
+	p.section = nil
+	p.explicit = false
+}
+
+func isMeta(name string) bool {
+	return slices.Contains(metaDirectives, name) || strings.HasPrefix(name, "x_")
+}
+
+// Splits lyrics line at chords in square brackets
+func parseSegments(line string) ([]model.ChordSegment, error) {
+	var segments []model.ChordSegment
+
+	current := model.ChordSegment{}
+
+	for {
+		i := strings.IndexByte(line, '[')
+		if i < 0 {
+			current.Lyrics += line
+			break
+		}
+
+		current.Lyrics += line[:i]
+
+		j := strings.IndexByte(line[i:], ']')
+		if j < 0 {
+			return nil, errors.Wrap(domain.ErrInvalidChordPro, "chord isn't closed")
+		}
+
+		name := line[i+1 : i+j]
+		if _, ok := parseChord(name); !ok {
+			return nil, errors.Wrapf(domain.ErrInvalidChordPro, "invalid chord %q", name)
+		}
+
+		if len(current.Chord) > 0 || len(current.Lyrics) > 0 {
+			segments = append(segments, current)
+		}
+
+		current = model.ChordSegment{Chord: name}
+		line = line[i+j+1:]
+	}
+
+	if len(current.Chord) > 0 || len(current.Lyrics) > 0 {
+		segments = append(segments, current)
+	}
+
+	return segments, nil
+}
@@ -0,0 +1,147 @@
+package chordpro
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+// Moves chords and key of sheet by semitones. Notes are spelled with accidentals,
+// by default transposed key decides spelling and without key original spelling of chords is kept
+func Transpose(sheet *model.ChordSheet, semitones int, accidentals string) {
+	if len(accidentals) == 0 {
+		if key, ok := parseChord(sheet.Key); ok && len(key.noChord) == 0 {
+			accidentals = keyAccidentals(key.transpose(semitones))
+		}
+	}
+
+	respell := func(name string) string {
+		c, ok := parseChord(name)
+		if !ok {
+			return name
+		}
+
+		return c.transpose(semitones).spell(accidentals)
+	}
+
+	if len(sheet.Key) > 0 {
+		sheet.Key = respell(sheet.Key)
+	}
+
+	for _, section := range sheet.Sections {
+		if section.Type == model.ChordTab {
+			continue
+		}
+
+		for _, line := range section.Lines {
+			for i := range line.Segments {
+				if len(line.Segments[i].Chord) > 0 {
+					line.Segments[i].Chord = respell(line.Segments[i].Chord)
+				}
+			}
+		}
+	}
+}
+
+// Renders sheet as plain text with chords aligned above lyrics
+func RenderText(sheet *model.ChordSheet) string {
+	var b strings.Builder
+
+	header := make([]string, 0, 3)
+
+	if len(sheet.Title) > 0 {
+		header = append(header, sheet.Title)
+	}
+
+	if len(sheet.Subtitle) > 0 {
+		header = append(header, sheet.Subtitle)
+	}
+
+	var info []string
+
+	if len(sheet.Key) > 0 {
+		info = append(info, "Key: "+sheet.Key)
+	}
+
+	if sheet.Capo > 0 {
+		info = append(info, fmt.Sprintf("Capo: %d", sheet.Capo))
+	}
+
+	if len(info) > 0 {
+		header = append(header, strings.Join(info, "  "))
+	}
+
+	if len(header) > 0 {
+		b.WriteString(strings.Join(header, "\n"))
+		b.WriteString("\n\n")
+	}
+
+	for i, section := range sheet.Sections {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+
+		if len(section.Label) > 0 {
+			fmt.Fprintf(&b, "[%s]\n", section.Label)
+		}
+
+		for _, line := range section.Lines {
+			switch {
+			case len(line.Comment) > 0:
+				fmt.Fprintf(&b, "(%s)\n", line.Comment)
+			case section.Type == model.ChordTab:
+				for _, segment := range line.Segments {
+					b.WriteString(segment.Lyrics)
+				}
+
+				b.WriteByte('\n')
+			default:
+				chords, lyrics := alignLine(line.Segments)
+
+				if len(chords) > 0 {
+					b.WriteString(chords)
+					b.WriteByte('\n')
+				}
+
+				if len(lyrics) > 0 {
+					b.WriteString(lyrics)
+					b.WriteByte('\n')
+				}
+			}
+		}
+	}
+
+	return b.String()
+}
+
+// Places chords above lyrics where they change, lyrics are padded when chords don't fit
+func alignLine(segments []model.ChordSegment) (string, string) {
+	var chords, lyrics strings.Builder
+
+	width := 0
+
+	for i, segment := range segments {
+		// Chord starts where its lyrics start
+		if pad := width - utf8.RuneCountInString(chords.String()); len(segment.Chord) > 0 && pad >= 0 {
+			chords.WriteString(strings.Repeat(" ", pad))
+		}
+
+		chords.WriteString(segment.Chord)
+
+		text := segment.Lyrics
+		length := utf8.RuneCountInString(text)
+
+		// Next chord needs a space after this one
+		if i+1 < len(segments) && len(segment.Chord) > 0 && length <= utf8.RuneCountInString(segment.Chord) {
+			text += strings.Repeat(" ", utf8.RuneCountInString(segment.Chord)+1-length)
+			length = utf8.RuneCountInString(segment.Chord) + 1
+		}
+
+		lyrics.WriteString(text)
+		width += length
+	}
+
+	return strings.TrimRight(chords.String(), " "), strings.TrimRight(lyrics.String(), " ")
+}
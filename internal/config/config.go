@@ -2,6 +2,7 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
@@ -10,6 +11,38 @@ import (
 const (
 	DefaultAddress  = ":8080"
 	DefaultLogLevel = "debug"
+
+	DefaultShutdownTimeout  = 10 * time.Second
+	DefaultReadTimeout      = 5 * time.Second
+	DefaultWriteTimeout     = 10 * time.Second
+	DefaultBulkTimeout      = 5 * time.Minute
+	DefaultMusicInfoTimeout = 10 * time.Second
+
+	DefaultMusicInfoRetryCount       = 3
+	DefaultMusicInfoRetryWait        = 200 * time.Millisecond
+	DefaultMusicInfoRetryMaxWait     = 2 * time.Second
+	DefaultMusicInfoBreakerThreshold = 5
+	DefaultMusicInfoBreakerCooldown  = 30 * time.Second
+
+	DefaultEnrichmentWorkers      = 2
+	DefaultEnrichmentPollInterval = time.Second
+	DefaultEnrichmentMaxAttempts  = 5
+	DefaultEnrichmentRetryDelay   = 30 * time.Second
+
+	DefaultRefreshBulkLimit = 100
+
+	DefaultTrashRetention     = 30 * 24 * time.Hour
+	DefaultTrashPurgeInterval = time.Hour
+	DefaultTrashPurgeBatch    = 100
+
+	DefaultPageSize    = 10
+	DefaultMaxPageSize = 100
+	DefaultFacetLimit  = 20
+
+	DefaultSearchConfig   = "english"
+	DefaultFuzzyThreshold = 0.3
+
+	DefaultDuplicateThreshold = 0.6
 )
 
 // App config struct
@@ -18,6 +51,57 @@ type Config struct {
 	LogLevel         string `mapstructure:"LOG_LEVEL"`
 	PostgresDSN      string `mapstructure:"DSN"`
 	MusicInfoAddress string `mapstructure:"MUSIC_INFO_ADDRESS"`
+
+	// Deadlines of DB reads, writes and bulk operations such as refresh of many songs
+	ReadTimeout  time.Duration `mapstructure:"DB_READ_TIMEOUT"`
+	WriteTimeout time.Duration `mapstructure:"DB_WRITE_TIMEOUT"`
+	BulkTimeout  time.Duration `mapstructure:"DB_BULK_TIMEOUT"`
+
+	// Deadline of music info request
+	MusicInfoTimeout time.Duration `mapstructure:"MUSIC_INFO_TIMEOUT"`
+
+	// Music info client retries and circuit breaker
+	MusicInfoRetryCount       uint          `mapstructure:"MUSIC_INFO_RETRY_COUNT"`
+	MusicInfoRetryWait        time.Duration `mapstructure:"MUSIC_INFO_RETRY_WAIT"`
+	MusicInfoRetryMaxWait     time.Duration `mapstructure:"MUSIC_INFO_RETRY_MAX_WAIT"`
+	MusicInfoBreakerThreshold uint          `mapstructure:"MUSIC_INFO_BREAKER_THRESHOLD"`
+	MusicInfoBreakerCooldown  time.Duration `mapstructure:"MUSIC_INFO_BREAKER_COOLDOWN"`
+
+	// Background song enrichment
+	EnrichmentWorkers      uint          `mapstructure:"ENRICHMENT_WORKERS"`
+	EnrichmentPollInterval time.Duration `mapstructure:"ENRICHMENT_POLL_INTERVAL"`
+	EnrichmentMaxAttempts  uint          `mapstructure:"ENRICHMENT_MAX_ATTEMPTS"`
+	EnrichmentRetryDelay   time.Duration `mapstructure:"ENRICHMENT_RETRY_DELAY"`
+
+	// Max number of songs refreshed by single bulk request
+	RefreshBulkLimit uint `mapstructure:"REFRESH_BULK_LIMIT"`
+
+	// Songs are kept in trash for retention period, zero retention keeps them forever
+	TrashRetention     time.Duration `mapstructure:"TRASH_RETENTION"`
+	TrashPurgeInterval time.Duration `mapstructure:"TRASH_PURGE_INTERVAL"`
+	TrashPurgeBatch    uint          `mapstructure:"TRASH_PURGE_BATCH"`
+
+	// Token of admin requests, admin routes are disabled when it's empty
+	AdminToken string `mapstructure:"ADMIN_TOKEN"`
+
+	// Number of listed songs when request doesn't set limit, and max allowed limit
+	PageSize    uint `mapstructure:"PAGE_SIZE"`
+	MaxPageSize uint `mapstructure:"MAX_PAGE_SIZE"`
+
+	// Max number of counted values of each song facet
+	FacetLimit uint `mapstructure:"FACET_LIMIT"`
+
+	// Text search configuration used when request doesn't specify one
+	SearchConfig string `mapstructure:"SEARCH_CONFIG"`
+
+	// Minimal trigram similarity of fuzzy name and group matches
+	FuzzyThreshold float64 `mapstructure:"FUZZY_THRESHOLD"`
+
+	// Minimal name similarity of duplicate song candidates
+	DuplicateThreshold float64 `mapstructure:"DUPLICATE_THRESHOLD"`
+
+	// Time given to active requests to finish on shutdown
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
 }
 
 // Reads config from app.env file and returns config struct
@@ -25,6 +109,36 @@ func NewConfig(path string) (*Config, error) {
 	cfg := &Config{
 		Address:  DefaultAddress,
 		LogLevel: DefaultLogLevel,
+
+		ReadTimeout:      DefaultReadTimeout,
+		WriteTimeout:     DefaultWriteTimeout,
+		BulkTimeout:      DefaultBulkTimeout,
+		MusicInfoTimeout: DefaultMusicInfoTimeout,
+		ShutdownTimeout:  DefaultShutdownTimeout,
+
+		MusicInfoRetryCount:       DefaultMusicInfoRetryCount,
+		MusicInfoRetryWait:        DefaultMusicInfoRetryWait,
+		MusicInfoRetryMaxWait:     DefaultMusicInfoRetryMaxWait,
+		MusicInfoBreakerThreshold: DefaultMusicInfoBreakerThreshold,
+		MusicInfoBreakerCooldown:  DefaultMusicInfoBreakerCooldown,
+
+		EnrichmentWorkers:      DefaultEnrichmentWorkers,
+		EnrichmentPollInterval: DefaultEnrichmentPollInterval,
+		EnrichmentMaxAttempts:  DefaultEnrichmentMaxAttempts,
+		EnrichmentRetryDelay:   DefaultEnrichmentRetryDelay,
+
+		TrashRetention:     DefaultTrashRetention,
+		TrashPurgeInterval: DefaultTrashPurgeInterval,
+		TrashPurgeBatch:    DefaultTrashPurgeBatch,
+
+		RefreshBulkLimit: DefaultRefreshBulkLimit,
+		PageSize:         DefaultPageSize,
+		MaxPageSize:      DefaultMaxPageSize,
+		FacetLimit:       DefaultFacetLimit,
+		SearchConfig:     DefaultSearchConfig,
+		FuzzyThreshold:   DefaultFuzzyThreshold,
+
+		DuplicateThreshold: DefaultDuplicateThreshold,
 	}
 
 	log.Printf("loading config from %s", path)
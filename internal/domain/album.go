@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+var (
+	ErrAlbumNotFound  = errors.New("album not found")
+	ErrDuplicateTrack = errors.New("song is listed on album more than once")
+)
+
+// Page of albums, newest added first
+type AlbumPage struct {
+	Albums     model.Albums `json:"albums"`
+	NextCursor *string      `json:"next_cursor" example:"42"`
+	Limit      uint         `json:"limit" example:"10"`
+}
+
+// Album fields set on create and edit
+type AlbumRequest struct {
+	Title       string     `json:"title" validate:"required,min=1" example:"Black Holes and Revelations"`
+	ArtistID    uint64     `json:"artist_id" validate:"required" example:"1"`
+	ReleaseDate *time.Time `json:"release_date" example:"2006-07-03T00:00:00Z"`
+	// Release type: lp, ep, single or compilation, lp by default
+	Type      model.AlbumType `json:"type" validate:"omitempty,oneof=lp ep single compilation" example:"lp"`
+	CoverLink string          `json:"cover_link" validate:"omitempty,http_url" example:"https://example.com/cover.jpg"`
+}
+
+type TrackRequest struct {
+	SongID uint64 `json:"song_id" validate:"required" example:"1"`
+	// Disc of track, first disc when zero
+	DiscNumber uint `json:"disc_number" example:"1"`
+}
+
+type SetTracksRequest struct {
+	// Tracks in playing order, they are numbered from 1 on each disc
+	Tracks []TrackRequest `json:"tracks" validate:"dive"`
+}
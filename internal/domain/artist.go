@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+var (
+	ErrArtistNotFound = errors.New("artist not found")
+	ErrArtistHasSongs = errors.New("artist has songs or albums and can't be deleted")
+	ErrAliasTaken     = errors.New("name belongs to another artist, merge aliases instead")
+	ErrInvalidName    = errors.New("artist name is empty")
+)
+
+// Page of artists ordered by slug
+type ArtistPage struct {
+	Artists    model.Artists `json:"artists"`
+	NextCursor *string       `json:"next_cursor" example:"the-beatles"`
+	Limit      uint          `json:"limit" example:"10"`
+}
+
+type RenameArtistRequest struct {
+	Name string `json:"name" validate:"required,min=1" example:"The Beatles"`
+}
+
+type MergeAliasesRequest struct {
+	// Names added to artist, artists known by them are merged into this one
+	Aliases []string `json:"aliases" validate:"required,min=1,dive,required" example:"Beatles,the beatles"`
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+var (
+	ErrInvalidChordPro = errors.New("invalid ChordPro document")
+	ErrNoChordSheet    = errors.New("song has no chord sheet")
+	ErrChordFormat     = errors.New("unsupported chord sheet format, expected text or json")
+	ErrAccidentals     = errors.New("unsupported accidentals, expected sharp or flat")
+	ErrTranspose       = errors.New("transposition must be between -11 and 11 semitones")
+)
+
+// Chord sheet format
+const (
+	ChordFormatText = "text"
+	ChordFormatJSON = "json"
+)
+
+// Rendering of chord sheet
+type ChordSheetQuery struct {
+	Format string
+	// Semitones chords are moved by
+	Transpose int
+	// Spelling of transposed chords, sharp or flat. By default it follows key
+	Accidentals string
+}
+
+// Chord sheet rendered in requested format
+type RenderedChordSheet struct {
+	Sheet *model.ChordSheet
+	Text  string
+}
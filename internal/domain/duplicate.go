@@ -0,0 +1,62 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrDuplicateSong = errors.New("song with same artist and name already exists")
+	ErrMergeSelf     = errors.New("song can't be merged into itself")
+	ErrThreshold     = errors.New("similarity threshold must be between 0 and 1")
+)
+
+// Song conflicting with existing one by artist and normalized name
+type DuplicateSongError struct {
+	ExistingID uint64
+}
+
+func (e *DuplicateSongError) Error() string {
+	return fmt.Sprintf("%s, id %d", ErrDuplicateSong, e.ExistingID)
+}
+
+func (e *DuplicateSongError) Unwrap() error {
+	return ErrDuplicateSong
+}
+
+type DuplicateSongResponse struct {
+	Error string `json:"error" example:"song with same artist and name already exists, id 12"`
+	// ID of existing song
+	ExistingID uint64 `json:"existing_id" example:"12"`
+} // @name DuplicateSongResponse
+
+// Pair of songs which are likely the same one
+type DuplicateCandidate struct {
+	SongID         uint64 `db:"song_id" json:"song_id" example:"12"`
+	SongName       string `db:"song_name" json:"song" example:"Supermassive Black Hole"`
+	SongGroup      string `db:"song_group" json:"group" example:"Muse"`
+	DuplicateID    uint64 `db:"duplicate_id" json:"duplicate_id" example:"31"`
+	DuplicateName  string `db:"duplicate_name" json:"duplicate_song" example:"Supermassive Black-Hole"`
+	DuplicateGroup string `db:"duplicate_group" json:"duplicate_group" example:"MUSE"`
+
+	// Trigram similarity of names and groups, from 0 to 1
+	NameSimilarity   float64 `db:"name_similarity" json:"name_similarity" example:"0.83"`
+	ArtistSimilarity float64 `db:"artist_similarity" json:"artist_similarity" example:"1"`
+	// Trigram similarity of lyrics, null when any of songs has no lyrics
+	LyricsSimilarity *float64 `db:"lyrics_similarity" json:"lyrics_similarity" example:"0.97"`
+	// Average of similarities
+	Score float64 `db:"score" json:"score" example:"0.93"`
+}
+
+// Candidates are ordered by score, most likely duplicates first
+type DuplicateReport struct {
+	Candidates []DuplicateCandidate `json:"candidates"`
+	// Minimal name similarity of candidates
+	Threshold float64 `json:"threshold" example:"0.6"`
+	Limit     uint    `json:"limit" example:"10"`
+}
+
+type MergeSongsRequest struct {
+	// ID of song merged into one in path, it's removed and redirects to kept song
+	DuplicateID uint64 `json:"duplicate_id" validate:"required" example:"31"`
+}
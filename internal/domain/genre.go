@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"errors"
+)
+
+var (
+	ErrGenreNotFound = errors.New("genre not found")
+	ErrGenreExists   = errors.New("genre with same slug already exists")
+	ErrGenreInUse    = errors.New("genre is set on songs and can't be deleted")
+)
+
+type AddGenreRequest struct {
+	Name string `json:"name" validate:"required,min=1" example:"Shoegaze"`
+}
+
+type SongFacetsRequest struct {
+	Filter SongFilter `json:"filter"`
+}
+
+// Number of songs matching filter with facet value
+type FacetCount struct {
+	// Genre slug, tag, artist ID or release year
+	Value string `db:"value" json:"value" example:"rock"`
+	Label string `db:"label" json:"label" example:"Rock"`
+	Count uint64 `db:"count" json:"count" example:"124"`
+}
+
+// Counts of songs matching filter, most common values first. Release years are ordered newest first
+type SongFacets struct {
+	Genres       []FacetCount `json:"genres"`
+	Tags         []FacetCount `json:"tags"`
+	Artists      []FacetCount `json:"artists"`
+	ReleaseYears []FacetCount `json:"release_years"`
+}
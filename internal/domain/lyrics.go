@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+var (
+	ErrLyricsQuery = errors.New("verse range, page and line references can't be combined")
+	ErrLineRef     = errors.New("invalid line reference, expected verse:line or verse:line-line")
+)
+
+// Requested verses or lines are outside of lyrics, valid range is reported
+type RangeError struct {
+	// Verse lines were requested from, nil when verses are out of range
+	Verse *int
+	// Valid indexes, last is -1 when there are none
+	First int
+	Last  int
+}
+
+func (e *RangeError) Error() string {
+	switch {
+	case e.Verse != nil:
+		return fmt.Sprintf("line is out of range, verse %d has lines %d-%d", *e.Verse, e.First, e.Last)
+	case e.Last < e.First:
+		return "verse is out of range, song has no verses"
+	}
+
+	return fmt.Sprintf("verse is out of range, song has verses %d-%d", e.First, e.Last)
+}
+
+// Lines of verse, line ends are included
+type LineRef struct {
+	Verse    int
+	LineFrom int
+	LineTo   int
+}
+
+// Part of lyrics selected by verse range, page or line references
+type LyricsQuery struct {
+	VerseFrom *int
+	VerseTo   *int
+	Page      uint
+	Limit     uint
+	Lines     []LineRef
+}
+
+// Line of lyrics addressed by verse and line index
+type LyricsLine struct {
+	Verse int    `json:"verse" example:"2"`
+	Line  int    `json:"line" example:"1"`
+	Text  string `json:"text" example:"You set my soul alight"`
+}
+
+type LyricsPage struct {
+	// Number of verses in lyrics
+	VerseCount int            `json:"verse_count" example:"6"`
+	Verses     model.Sections `json:"verses"`
+	// Lines requested by references
+	Lines []LyricsLine `json:"lines,omitempty"`
+	// There are verses after selected ones
+	HasMore bool `json:"has_more"`
+}
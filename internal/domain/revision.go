@@ -0,0 +1,38 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+var ErrRevisionNotFound = errors.New("song revision not found")
+
+// Authors of changes made by service itself and by unnamed clients
+const (
+	SystemAuthor    = "system"
+	AnonymousAuthor = "anonymous"
+)
+
+// Author of song change and its reason, saved with revision
+type ChangeNote struct {
+	Author string
+	Reason string
+}
+
+type RevisionPage struct {
+	Revisions model.SongRevisions `json:"revisions"`
+	// Cursor of next page, null on last page
+	NextCursor *string `json:"next_cursor" example:"12"`
+	Limit      uint    `json:"limit" example:"10"`
+}
+
+// Changes between two song revisions
+type RevisionDiff struct {
+	From uint64 `json:"from" example:"2"`
+	To   uint64 `json:"to" example:"3"`
+	// Changes of fields other than lyrics
+	Changes []FieldChange `json:"changes"`
+	// Unified diff of lyrics lines, empty when lyrics are same
+	Diff string `json:"diff" example:"--- revision 2\n+++ revision 3\n@@ -1 +1 @@\n-old line\n+new line\n"`
+}
@@ -1,48 +1,241 @@
 package domain
 
 import (
+	"encoding/json"
 	"errors"
 	"time"
+
+	"github.com/Sadere/song-depository/internal/metadata"
+	"github.com/Sadere/song-depository/internal/model"
 )
 
 var (
-	ErrNoSongs       = errors.New("no songs found")
-	ErrSongDetail    = errors.New("failed to retrieve song detail")
-	ErrSongNotFound  = errors.New("song with provided ID not found")
-	ErrVerseNotFound = errors.New("requested verse doesn't exist in this song")
+	ErrSongNotFound    = errors.New("song with provided ID not found")
+	ErrVerseNotFound   = errors.New("requested section doesn't exist in this song")
+	ErrSectionType     = errors.New("unsupported section type, expected verse, chorus, bridge, intro or outro")
+	ErrVersionMismatch = errors.New("song was modified, version doesn't match")
+	ErrReadOnlyField   = errors.New("field is read-only")
+	ErrInvalidSong     = errors.New("song is invalid")
+	ErrPatchType       = errors.New("unsupported patch content type")
+	ErrSearchConfig    = errors.New("unsupported text search configuration")
+	ErrMatchMode       = errors.New("unsupported match mode, expected contains, exact or fuzzy")
+	ErrFuzzyThreshold  = errors.New("fuzzy threshold must be between 0 and 1")
+	ErrInvalidCursor   = errors.New("invalid page cursor")
+	ErrPageLimit       = errors.New("page limit exceeds maximum")
+	ErrSortField       = errors.New("unsupported sort field, expected name, group, release_date, created_at or updated_at")
+	ErrInvalidRange    = errors.New("range start is after its end")
+	ErrUnknownField    = errors.New("unsupported song field")
+	ErrCreditRole      = errors.New("unsupported credit role, expected featuring, composer, lyricist or producer")
+	ErrInvalidTag      = errors.New("tag must be 1 to 64 characters long")
+	ErrSongNotDeleted  = errors.New("song isn't in trash")
 )
 
 type ErrorResponse struct {
 	Error string `json:"error" example:"Fatal error"`
 } // @name ErrorResponse
 
+type StatusResponse struct {
+	MusicInfo metadata.BreakerStatus `json:"music_info"`
+} // @name StatusResponse
+
+// Name and group match modes
+const (
+	MatchContains = "contains"
+	MatchExact    = "exact"
+	MatchFuzzy    = "fuzzy"
+)
+
 type SongFilter struct {
 	Name        *string    `json:"name"`
 	Group       *string    `json:"group"`
+	ArtistID    *uint64    `json:"artist_id" example:"1"`
 	Text        *string    `json:"text"`
 	ReleaseDate *time.Time `json:"release_date" example:"2024-10-29T15:04:05.000Z"`
+
+	// Songs crediting artist in any role
+	CreditedArtistID *uint64 `json:"credited_artist_id" example:"2"`
+
+	// Songs of any of genre slugs
+	Genres []string `json:"genres" example:"rock,pop"`
+
+	// Songs tagged with any, all or none of tags
+	TagsAny  []string `json:"tags_any" example:"live,acoustic"`
+	TagsAll  []string `json:"tags_all" example:"space"`
+	TagsNone []string `json:"tags_none" example:"cover"`
+
+	// Release date range, both ends are included
+	ReleaseDateFrom *time.Time `json:"release_date_from" example:"2000-01-01T00:00:00Z"`
+	ReleaseDateTo   *time.Time `json:"release_date_to" example:"2009-12-31T00:00:00Z"`
+
+	// Songs added within range, ends are excluded
+	CreatedAfter  *time.Time `json:"created_after" example:"2024-10-01T00:00:00Z"`
+	CreatedBefore *time.Time `json:"created_before" example:"2024-11-01T00:00:00Z"`
+
+	// Songs changed at or after time
+	UpdatedSince *time.Time `json:"updated_since" example:"2024-10-29T15:04:05.000Z"`
+
+	// Full text search query, supports "phrases", prefix*, -negation and OR
+	Query *string `json:"q" example:"\"soul alight\" -baby"`
+	// Text search configuration: english, russian or simple
+	SearchConfig string `json:"search_config" example:"english"`
+
+	// How name and group are matched, case and accent insensitive: contains, exact or fuzzy
+	Match string `json:"match" example:"fuzzy"`
+	// Minimal similarity of fuzzy match, from 0 to 1
+	FuzzyThreshold *float64 `json:"fuzzy_threshold" example:"0.3"`
+
+	// Songs in trash instead of live ones
+	Deleted bool `json:"-"`
 }
 
 type ListSongsRequest struct {
 	Filter SongFilter `json:"filter"`
-	Page   uint       `json:"page"`
+	// Cursor of requested page, first page is returned when empty
+	Cursor string `json:"cursor" example:"eyJzIjoiLWlkIiwidiI6WyI0MiJdfQ"`
+	// Number of songs on page, configured default is used when zero
+	Limit uint `json:"limit" example:"10"`
+	// Sort fields: name, group, release_date, created_at or updated_at, "-" prefix sorts descending.
+	// Search hits are ordered by relevance when empty, other songs by newest first
+	Sort []string `json:"sort" example:"-release_date,name"`
+	// Song fields to return, lyrics are replaced with text_length and verse_count when empty
+	Fields []string `json:"fields" example:"song,group,release_date"`
+	// Count all songs matching filter
+	Total bool `json:"total"`
+	// Add highlighted lyrics snippet to search hits
+	Highlight bool `json:"highlight"`
+}
+
+// Options of song list query
+type ListOptions struct {
+	Cursor    string
+	Limit     uint
+	Sort      []string
+	Fields    model.FieldSet
+	Total     bool
+	Highlight bool
+}
+
+// Page of song list with cursors of adjacent pages
+type SongPage struct {
+	Songs      model.Songs `json:"songs"`
+	NextCursor *string     `json:"next_cursor" example:"eyJzIjoiLWlkIiwidiI6WyIzMiJdfQ"`
+	PrevCursor *string     `json:"prev_cursor"`
+	Limit      uint        `json:"limit" example:"10"`
+	// Number of songs matching filter, only counted on request
+	Total *uint64 `json:"total,omitempty" example:"125"`
+
+	// Fields songs are encoded with
+	Fields model.FieldSet `json:"-"`
+}
+
+func (p SongPage) MarshalJSON() ([]byte, error) {
+	type page SongPage
+
+	songs := make([]model.PartialSong, len(p.Songs))
+
+	for i, song := range p.Songs {
+		songs[i] = model.PartialSong{Song: song, Fields: p.Fields}
+	}
+
+	return json.Marshal(struct {
+		Songs []model.PartialSong `json:"songs"`
+		page
+	}{songs, page(p)})
 }
 
 type AddSongRequest struct {
-	Name  string `json:"song" validate:"required,min=1"`
+	Name string `json:"song" validate:"required,min=1"`
+	// Artist name or any of its aliases, unknown name adds new artist
 	Group string `json:"group" validate:"required,min=1"`
 }
 
+type AddSongResponse struct {
+	ID        uint64 `json:"id" example:"1"`
+	Status    string `json:"enrichment_status" example:"pending"`
+	StatusURL string `json:"status_url" example:"/song/1/status"`
+}
+
+// Content types of song patch documents
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// Editable song fields validated after patch
+type SongFields struct {
+	Name        string        `json:"song" validate:"required,min=1"`
+	Group       string        `json:"group" validate:"required,min=1"`
+	Text        string        `json:"text"`
+	ReleaseDate *time.Time    `json:"release_date"`
+	Link        string        `json:"link" validate:"omitempty,http_url"`
+	Credits     model.Credits `json:"credits"`
+	Genres      model.Labels  `json:"genres"`
+	Tags        model.Labels  `json:"tags"`
+}
+
 type UpdateSongRequest struct {
 	Name        string     `json:"song"`
 	Group       string     `json:"group"`
 	Text        string     `json:"text"`
 	ReleaseDate *time.Time `json:"release_date" example:"2024-10-29T15:04:05.000Z"`
 	Link        string     `json:"link" validate:"http_url"`
+	// Replace credits besides primary one, which follows group. Empty list removes them
+	Credits []CreditRequest `json:"credits" validate:"omitempty,dive"`
+	// Replace genre slugs, empty list removes them
+	Genres []string `json:"genres" example:"rock"`
+	// Replace tags, empty list removes them
+	Tags []string `json:"tags" example:"space,falsetto"`
+}
+
+type CreditRequest struct {
+	// Artist name, resolved through aliases
+	Artist string `json:"artist" validate:"required" example:"Brian Eno"`
+	// Credit role: featuring, composer, lyricist or producer
+	Role model.CreditRole `json:"role" validate:"oneof=featuring composer lyricist producer" example:"producer"`
+}
+
+type RefreshSongRequest struct {
+	// Only report changes without saving them
+	DryRun bool `json:"dry_run"`
+	// Overwrite fields set by hand
+	Force bool `json:"force"`
+}
+
+type RefreshSongsRequest struct {
+	Filter SongFilter `json:"filter"`
+	RefreshSongRequest
+}
+
+// Change of song field found on refresh
+type FieldChange struct {
+	Field string `json:"field" example:"text"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+	// Field was set by hand and is kept
+	Protected bool `json:"protected,omitempty"`
+}
+
+type RefreshResult struct {
+	SongID  uint64        `json:"id" example:"1"`
+	Changes []FieldChange `json:"changes"`
+	// Changes were saved
+	Applied bool   `json:"applied"`
+	Error   string `json:"error,omitempty"`
+}
+
+type RefreshSongsResponse struct {
+	Results []RefreshResult `json:"results"`
+}
+
+// Lyrics sections selected by index, type or label
+type SectionQuery struct {
+	Index *int
+	Type  model.SectionType
+	Label string
 }
 
-type SongDetail struct {
-	ReleaseDate string `json:"releaseDate"`
-	Text        string `json:"text"`
-	Link        string `json:"link"`
+type SongTextResponse struct {
+	// Text of selected sections separated by blank line
+	Text     string         `json:"text" example:"Ooh\nYou set my soul alight"`
+	Sections model.Sections `json:"sections"`
 }
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"errors"
+
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+var (
+	ErrInvalidLRC     = errors.New("invalid LRC line, expected [mm:ss.xx] timestamp or tag")
+	ErrTimestampOrder = errors.New("timestamps must not go backward")
+	ErrNoTimedLyrics  = errors.New("song has no synchronized lyrics")
+)
+
+// Line sung at playback position
+type ActiveLine struct {
+	Line *model.TimedLine `json:"line"`
+	// Index of word sung at position, set when line has word timings
+	Word *int `json:"word,omitempty" example:"2"`
+}
+
+// Synchronized lyrics state at playback position
+type ActiveLyrics struct {
+	Position int64 `json:"position_ms" example:"13400"`
+	// Lines starting at same time are active together, empty before first line, during breaks and after end of last one
+	Lines []ActiveLine `json:"lines"`
+	// First line starting after position
+	Next *model.TimedLine `json:"next,omitempty"`
+}
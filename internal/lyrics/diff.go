@@ -0,0 +1,332 @@
+package lyrics
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Unchanged lines shown around changes
+const diffContext = 3
+
+type editKind byte
+
+const (
+	editKeep   editKind = ' '
+	editDelete editKind = '-'
+	editInsert editKind = '+'
+)
+
+type edit struct {
+	kind editKind
+	line string
+	// Line positions in old and new text before this edit
+	from, to int
+}
+
+// Returns unified diff of text lines, empty when texts are same
+func UnifiedDiff(from, to, fromLabel, toLabel string) string {
+	edits := diffLines(splitLines(from), splitLines(to))
+
+	var hunks []string
+
+	for start := 0; start < len(edits); {
+		// Skip to next change
+		for start < len(edits) && edits[start].kind == editKeep {
+			start++
+		}
+
+		if start == len(edits) {
+			break
+		}
+
+		// Changes separated by less than twice the context share hunk
+		end := start
+
+		for i := start; i < len(edits); i++ {
+			if edits[i].kind != editKeep {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+
+		first := max(start-diffContext, 0)
+		last := min(end+diffContext, len(edits))
+
+		hunks = append(hunks, formatHunk(edits[first:last]))
+		start = last
+	}
+
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("--- %s\n+++ %s\n%s", fromLabel, toLabel, strings.Join(hunks, ""))
+}
+
+func formatHunk(edits []edit) string {
+	var (
+		b                  strings.Builder
+		fromCount, toCount int
+	)
+
+	for _, e := range edits {
+		if e.kind != editInsert {
+			fromCount++
+		}
+
+		if e.kind != editDelete {
+			toCount++
+		}
+
+		fmt.Fprintf(&b, "%c%s\n", e.kind, e.line)
+	}
+
+	return fmt.Sprintf("@@ -%s +%s @@\n", hunkRange(edits[0].from, fromCount), hunkRange(edits[0].to, toCount)) + b.String()
+}
+
+// Formats hunk line range, empty range refers to line before it
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// Finds shortest edit script of lines with Myers' algorithm in linear space
+func diffLines(a, b []string) []edit {
+	d := &differ{
+		a:     a,
+		b:     b,
+		edits: make([]edit, 0, len(a)+len(b)),
+	}
+
+	d.compare(0, 0, len(a), len(b))
+
+	edits := d.edits
+
+	// Deleted lines go before inserted ones
+	for start := 0; start < len(edits); start++ {
+		if edits[start].kind == editKeep {
+			continue
+		}
+
+		end := start
+		for end < len(edits) && edits[end].kind != editKeep {
+			end++
+		}
+
+		changes := make([]edit, 0, end-start)
+
+		for _, kind := range []editKind{editDelete, editInsert} {
+			for _, e := range edits[start:end] {
+				if e.kind == kind {
+					changes = append(changes, e)
+				}
+			}
+		}
+
+		copy(edits[start:end], changes)
+
+		start = end
+	}
+
+	from, to := 0, 0
+
+	for i := range edits {
+		edits[i].from, edits[i].to = from, to
+
+		if edits[i].kind != editInsert {
+			from++
+		}
+
+		if edits[i].kind != editDelete {
+			to++
+		}
+	}
+
+	return edits
+}
+
+// Builds edit script of two line lists
+type differ struct {
+	a, b  []string
+	edits []edit
+}
+
+// Diagonal run of edit path with single edit step before or after it
+type snake struct {
+	fromX, fromY int
+	toX, toY     int
+	// Edit step goes before diagonal
+	stepFirst bool
+}
+
+func (d *differ) add(kind editKind, line string) {
+	d.edits = append(d.edits, edit{kind: kind, line: line})
+}
+
+// Compares a[left:right] with b[top:bottom]. Common first and last lines are matched
+// before comparing the rest, which is split at middle snake of its edit path
+func (d *differ) compare(left, top, right, bottom int) {
+	for left < right && top < bottom && d.a[left] == d.b[top] {
+		d.add(editKeep, d.a[left])
+		left++
+		top++
+	}
+
+	suffix := 0
+	for left < right-suffix && top < bottom-suffix && d.a[right-1-suffix] == d.b[bottom-1-suffix] {
+		suffix++
+	}
+
+	right -= suffix
+	bottom -= suffix
+
+	switch {
+	case left == right:
+		for _, line := range d.b[top:bottom] {
+			d.add(editInsert, line)
+		}
+	case top == bottom:
+		for _, line := range d.a[left:right] {
+			d.add(editDelete, line)
+		}
+	default:
+		s := d.middleSnake(left, top, right, bottom)
+
+		d.compare(left, top, s.fromX, s.fromY)
+		d.addSnake(s)
+		d.compare(s.toX, s.toY, right, bottom)
+	}
+
+	for _, line := range d.a[right : right+suffix] {
+		d.add(editKeep, line)
+	}
+}
+
+func (d *differ) addSnake(s snake) {
+	x, y := s.fromX, s.fromY
+	dx, dy := s.toX-s.fromX, s.toY-s.fromY
+
+	step := func() {
+		switch {
+		case dx > dy:
+			d.add(editDelete, d.a[x])
+			x++
+		case dy > dx:
+			d.add(editInsert, d.b[y])
+			y++
+		}
+	}
+
+	if s.stepFirst {
+		step()
+	}
+
+	for i := 0; i < min(dx, dy); i++ {
+		d.add(editKeep, d.a[x])
+		x++
+		y++
+	}
+
+	if !s.stepFirst {
+		step()
+	}
+}
+
+// Searches shortest edit path from both corners of range at once until paths overlap,
+// only furthest point of each diagonal is kept. Range must have no common first and last lines
+func (d *differ) middleSnake(left, top, right, bottom int) snake {
+	delta := (right - left) - (bottom - top)
+	limit := (right - left + bottom - top + 1) / 2
+
+	// Diagonals k = x - y relative to range corner are stored at k + offset
+	offset := limit + 1
+	forward := make([]int, 2*limit+3)
+	backward := make([]int, 2*limit+3)
+	forward[offset+1] = left
+	backward[offset+1] = bottom
+
+	for depth := 0; depth <= limit; depth++ {
+		for k := depth; k >= -depth; k -= 2 {
+			var x, px int
+
+			if k == -depth || (k != depth && forward[offset+k-1] < forward[offset+k+1]) {
+				px = forward[offset+k+1]
+				x = px
+			} else {
+				px = forward[offset+k-1]
+				x = px + 1
+			}
+
+			y := top + (x - left) - k
+
+			py := y
+			if depth > 0 && x == px {
+				py = y - 1
+			}
+
+			for x < right && y < bottom && d.a[x] == d.b[y] {
+				x++
+				y++
+			}
+
+			forward[offset+k] = x
+
+			// Odd delta means paths can first meet after forward step
+			c := k - delta
+			if delta%2 != 0 && c > -depth && c < depth && y >= backward[offset+c] {
+				return snake{fromX: px, fromY: py, toX: x, toY: y, stepFirst: true}
+			}
+		}
+
+		for c := depth; c >= -depth; c -= 2 {
+			var y, py int
+
+			if c == -depth || (c != depth && backward[offset+c-1] > backward[offset+c+1]) {
+				py = backward[offset+c+1]
+				y = py
+			} else {
+				py = backward[offset+c-1]
+				y = py - 1
+			}
+
+			k := c + delta
+			x := left + (y - top) + k
+
+			px := x
+			if depth > 0 && y == py {
+				px = x + 1
+			}
+
+			for x > left && y > top && d.a[x-1] == d.b[y-1] {
+				x--
+				y--
+			}
+
+			backward[offset+c] = y
+
+			// Even delta means paths can first meet after backward step
+			if delta%2 == 0 && k >= -depth && k <= depth && x <= forward[offset+k] {
+				return snake{fromX: x, fromY: y, toX: px, toY: py}
+			}
+		}
+	}
+
+	// Paths always meet within limit
+	panic("lyrics: middle snake not found")
+}
+
+func splitLines(text string) []string {
+	if len(text) == 0 {
+		return nil
+	}
+
+	return strings.Split(text, "\n")
+}
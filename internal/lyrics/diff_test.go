@@ -0,0 +1,132 @@
+package lyrics
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// Returns lines l1..ln with given lines replaced
+func numberedLines(n int, replace map[int]string) string {
+	lines := make([]string, n)
+
+	for i := range lines {
+		lines[i] = fmt.Sprintf("l%d", i+1)
+
+		if line, ok := replace[i+1]; ok {
+			lines[i] = line
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want string
+	}{
+		{
+			name: "same texts",
+			from: "a\nb",
+			to:   "a\nb",
+			want: "",
+		},
+		{
+			name: "both empty",
+			want: "",
+		},
+		{
+			name: "changed line",
+			from: "a\nb\nc",
+			to:   "a\nx\nc",
+			want: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+x\n c\n",
+		},
+		{
+			name: "removed line",
+			from: "a\nb\nc",
+			to:   "a\nc",
+			want: "--- old\n+++ new\n@@ -1,3 +1,2 @@\n a\n-b\n c\n",
+		},
+		{
+			name: "added lines to empty text",
+			to:   "a\nb",
+			want: "--- old\n+++ new\n@@ -0,0 +1,2 @@\n+a\n+b\n",
+		},
+		{
+			name: "all lines removed",
+			from: "a\nb",
+			want: "--- old\n+++ new\n@@ -1,2 +0,0 @@\n-a\n-b\n",
+		},
+		{
+			name: "single line hunk",
+			from: "a",
+			to:   "b",
+			want: "--- old\n+++ new\n@@ -1 +1 @@\n-a\n+b\n",
+		},
+		{
+			name: "nearby changes share hunk",
+			from: numberedLines(8, nil),
+			to:   numberedLines(8, map[int]string{2: "x", 7: "y"}),
+			want: "--- old\n+++ new\n@@ -1,8 +1,8 @@\n l1\n-l2\n+x\n l3\n l4\n l5\n l6\n-l7\n+y\n l8\n",
+		},
+		{
+			name: "distant changes are split into hunks",
+			from: numberedLines(20, nil),
+			to:   numberedLines(20, map[int]string{2: "x", 18: "y"}),
+			want: "--- old\n+++ new\n" +
+				"@@ -1,5 +1,5 @@\n l1\n-l2\n+x\n l3\n l4\n l5\n" +
+				"@@ -15,6 +15,6 @@\n l15\n l16\n l17\n-l18\n+y\n l19\n l20\n",
+		},
+		{
+			name: "interleaved changes group deletions first",
+			from: "a\nb\nc\nd\ne",
+			to:   "a\nx\nc\ny\ne",
+			want: "--- old\n+++ new\n@@ -1,5 +1,5 @@\n a\n-b\n+x\n c\n-d\n+y\n e\n",
+		},
+		{
+			name: "replaced block",
+			from: "a\nb\nc\nd",
+			to:   "a\nx\ny\nz\nd",
+			want: "--- old\n+++ new\n@@ -1,4 +1,5 @@\n a\n-b\n-c\n+x\n+y\n+z\n d\n",
+		},
+		{
+			name: "moved line",
+			from: "a\nb\nc\nd",
+			to:   "b\nc\na\nd",
+			want: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-a\n b\n c\n+a\n d\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnifiedDiff(tt.from, tt.to, "old", "new")
+
+			if got != tt.want {
+				t.Errorf("UnifiedDiff() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnifiedDiffLargeTexts(t *testing.T) {
+	// Table of all line pairs would take gigabytes
+	const n = 100000
+
+	from := numberedLines(n, nil)
+	to := numberedLines(n, map[int]string{10: "x", n / 2: "y", n - 10: "z"})
+
+	got := UnifiedDiff(from, to, "old", "new")
+
+	for _, want := range []string{"-l10\n+x\n", fmt.Sprintf("-l%d\n+y\n", n/2), fmt.Sprintf("-l%d\n+z\n", n-10)} {
+		if !strings.Contains(got, want) {
+			t.Errorf("diff has no %q", want)
+		}
+	}
+
+	if hunks := strings.Count(got, "@@ -"); hunks != 3 {
+		t.Errorf("diff has %d hunks, want 3", hunks)
+	}
+}
@@ -0,0 +1,261 @@
+package lyrics
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/pkg/errors"
+)
+
+// Timestamp such as 01:02.50, 01:02.500 or 01:02
+const timestamp = `(\d{1,3}):([0-5]?\d)(?:[.:](\d{1,3}))?`
+
+var (
+	lineStampPattern = regexp.MustCompile(`^\[` + timestamp + `\]`)
+	wordStampPattern = regexp.MustCompile(`<` + timestamp + `>`)
+	tagPattern       = regexp.MustCompile(`^\[([A-Za-z]+):(.*)\]$`)
+	spacePattern     = regexp.MustCompile(`\s+`)
+)
+
+// Parses LRC or enhanced LRC lyrics. Line timestamps must not go backward,
+// word timestamps must not go backward within line and must not precede its start.
+// Lines with several timestamps are repeated at each of them and placed by their timestamps
+func ParseLRC(text string) (model.TimedLines, error) {
+	var (
+		lines  model.TimedLines
+		offset int64
+		last   int64
+	)
+
+	for n, raw := range strings.Split(lineBreaks.Replace(text), "\n") {
+		raw = strings.TrimSpace(raw)
+		if len(raw) == 0 {
+			continue
+		}
+
+		var starts []int64
+
+		for {
+			m := lineStampPattern.FindStringSubmatch(raw)
+			if m == nil {
+				break
+			}
+
+			starts = append(starts, stampMillis(m[1], m[2], m[3]))
+			raw = raw[len(m[0]):]
+		}
+
+		// Metadata tag, only offset affects timing
+		if len(starts) == 0 {
+			tag := tagPattern.FindStringSubmatch(raw)
+			if tag == nil {
+				return nil, errors.Wrapf(domain.ErrInvalidLRC, "line %d", n+1)
+			}
+
+			if strings.EqualFold(tag[1], "offset") {
+				var err error
+
+				offset, err = strconv.ParseInt(strings.TrimSpace(tag[2]), 10, 64)
+				if err != nil {
+					return nil, errors.Wrapf(domain.ErrInvalidLRC, "line %d: offset", n+1)
+				}
+			}
+
+			continue
+		}
+
+		// Repeats of compressed LRC line may point anywhere in lyrics
+		if len(starts) == 1 {
+			if starts[0] < last {
+				return nil, errors.Wrapf(domain.ErrTimestampOrder, "line %d", n+1)
+			}
+
+			last = starts[0]
+		}
+
+		for _, start := range starts {
+			line, err := parseWords(raw, start)
+			if err != nil {
+				return nil, errors.Wrapf(err, "line %d", n+1)
+			}
+
+			lines = append(lines, line)
+		}
+	}
+
+	// Lines sharing start keep file order
+	slices.SortStableFunc(lines, func(a, b *model.TimedLine) int {
+		return cmp.Compare(a.Start, b.Start)
+	})
+
+	for i, line := range lines {
+		line.Index = i
+	}
+
+	// Positive offset shows lyrics earlier
+	if offset != 0 {
+		shift := func(ms int64) int64 {
+			return max(ms-offset, 0)
+		}
+
+		for _, line := range lines {
+			line.Start = shift(line.Start)
+
+			if line.End != nil {
+				end := shift(*line.End)
+				line.End = &end
+			}
+
+			for i := range line.Words {
+				line.Words[i].Start = shift(line.Words[i].Start)
+			}
+		}
+	}
+
+	return lines, nil
+}
+
+// Splits line text by word timestamps, text before first of them starts with line
+func parseWords(body string, start int64) (*model.TimedLine, error) {
+	line := &model.TimedLine{
+		Start: start,
+	}
+
+	marks := wordStampPattern.FindAllStringSubmatchIndex(body, -1)
+	if marks == nil {
+		line.Text = strings.TrimSpace(spacePattern.ReplaceAllString(body, " "))
+		return line, nil
+	}
+
+	addWord := func(at int64, segment string) {
+		segment = spacePattern.ReplaceAllString(segment, " ")
+		if len(strings.TrimSpace(segment)) > 0 {
+			line.Words = append(line.Words, model.TimedWord{Start: at, Text: segment})
+		}
+	}
+
+	addWord(start, body[:marks[0][0]])
+
+	prev := start
+
+	for i, m := range marks {
+		at := stampMillis(submatch(body, m, 1), submatch(body, m, 2), submatch(body, m, 3))
+		if at < prev {
+			return nil, domain.ErrTimestampOrder
+		}
+
+		prev = at
+
+		end := len(body)
+		if i+1 < len(marks) {
+			end = marks[i+1][0]
+		}
+
+		segment := body[m[1]:end]
+
+		// Trailing timestamp marks end of last word
+		if i+1 == len(marks) && len(strings.TrimSpace(segment)) == 0 {
+			line.End = &at
+			continue
+		}
+
+		addWord(at, segment)
+	}
+
+	if len(line.Words) > 0 {
+		first, last := &line.Words[0], &line.Words[len(line.Words)-1]
+		first.Text = strings.TrimLeft(first.Text, " ")
+		last.Text = strings.TrimRight(last.Text, " ")
+	}
+
+	var text strings.Builder
+
+	for _, word := range line.Words {
+		text.WriteString(word.Text)
+	}
+
+	line.Text = text.String()
+
+	return line, nil
+}
+
+// Formats lyrics as LRC, word timings are kept when enhanced
+func FormatLRC(title, artist string, lines model.TimedLines, enhanced bool) string {
+	var b strings.Builder
+
+	if len(title) > 0 {
+		fmt.Fprintf(&b, "[ti:%s]\n", title)
+	}
+
+	if len(artist) > 0 {
+		fmt.Fprintf(&b, "[ar:%s]\n", artist)
+	}
+
+	for _, line := range lines {
+		fmt.Fprintf(&b, "[%s]", formatStamp(line.Start))
+
+		if !enhanced || len(line.Words) == 0 {
+			b.WriteString(line.Text)
+			b.WriteByte('\n')
+
+			continue
+		}
+
+		for _, word := range line.Words {
+			fmt.Fprintf(&b, "<%s>%s", formatStamp(word.Start), word.Text)
+		}
+
+		if line.End != nil {
+			fmt.Fprintf(&b, " <%s>", formatStamp(*line.End))
+		}
+
+		b.WriteByte('\n')
+	}
+
+	return b.String()
+}
+
+// Converts timestamp parts to milliseconds, fraction of 1 or 2 digits is tenths or hundredths
+func stampMillis(minutes, seconds, fraction string) int64 {
+	m, _ := strconv.ParseInt(minutes, 10, 64)
+	s, _ := strconv.ParseInt(seconds, 10, 64)
+
+	ms := (m*60 + s) * 1000
+
+	if len(fraction) > 0 {
+		f, _ := strconv.ParseInt(fraction, 10, 64)
+		for i := len(fraction); i < 3; i++ {
+			f *= 10
+		}
+
+		ms += f
+	}
+
+	return ms
+}
+
+// Formats milliseconds as mm:ss.xx, millisecond precision is kept when needed
+func formatStamp(ms int64) string {
+	stamp := fmt.Sprintf("%02d:%02d", ms/60000, ms/1000%60)
+
+	if ms%10 == 0 {
+		return stamp + fmt.Sprintf(".%02d", ms%1000/10)
+	}
+
+	return stamp + fmt.Sprintf(".%03d", ms%1000)
+}
+
+// Returns text of regexp submatch, empty when group didn't match
+func submatch(s string, m []int, group int) string {
+	if m[2*group] < 0 {
+		return ""
+	}
+
+	return s[m[2*group]:m[2*group+1]]
+}
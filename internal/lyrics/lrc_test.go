@@ -0,0 +1,248 @@
+package lyrics
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
+
+func TestParseLRC(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want model.TimedLines
+		err  error
+	}{
+		{
+			name: "empty",
+			text: "",
+		},
+		{
+			name: "plain lines with tags",
+			text: "[ti:Song]\n[ar:Artist]\n[by:someone]\n\n[00:01.00]  Hello   world \n[00:02.50]Again\n",
+			want: model.TimedLines{
+				{Index: 0, Start: 1000, Text: "Hello world"},
+				{Index: 1, Start: 2500, Text: "Again"},
+			},
+		},
+		{
+			name: "timestamp precisions",
+			text: "[1:02]a\n[01:02.05]c\n[01:02.051]d\n[01:02.5]b\n[01:02:50]e\n[100:00.00]f",
+			want: model.TimedLines{
+				{Index: 0, Start: 62000, Text: "a"},
+				{Index: 1, Start: 62050, Text: "c"},
+				{Index: 2, Start: 62051, Text: "d"},
+				{Index: 3, Start: 62500, Text: "b"},
+				{Index: 4, Start: 62500, Text: "e"},
+				{Index: 5, Start: 6000000, Text: "f"},
+			},
+		},
+		{
+			name: "compressed lines are ordered by timestamp",
+			text: "[00:10.00][00:30.00]Chorus\r\n[00:05.00]Intro\r\n[00:20.00]Verse",
+			want: model.TimedLines{
+				{Index: 0, Start: 5000, Text: "Intro"},
+				{Index: 1, Start: 10000, Text: "Chorus"},
+				{Index: 2, Start: 20000, Text: "Verse"},
+				{Index: 3, Start: 30000, Text: "Chorus"},
+			},
+		},
+		{
+			name: "lines sharing start keep file order",
+			text: "[00:01.00][00:03.00]a\n[00:01.00]b\n[00:03.00]c",
+			want: model.TimedLines{
+				{Index: 0, Start: 1000, Text: "a"},
+				{Index: 1, Start: 1000, Text: "b"},
+				{Index: 2, Start: 3000, Text: "a"},
+				{Index: 3, Start: 3000, Text: "c"},
+			},
+		},
+		{
+			name: "positive offset shows lyrics earlier",
+			text: "[offset:+500]\n[00:00.20]a\n[00:01.00]<00:01.00>b <00:01.40>c <00:02.00>",
+			want: model.TimedLines{
+				{Index: 0, Start: 0, Text: "a"},
+				{
+					Index: 1, Start: 500, Text: "b c", End: int64Ptr(1500),
+					Words: model.TimedWords{{Start: 500, Text: "b "}, {Start: 900, Text: "c"}},
+				},
+			},
+		},
+		{
+			name: "negative offset shows lyrics later",
+			text: "[00:01.00]a\n[OFFSET: -250]",
+			want: model.TimedLines{
+				{Index: 0, Start: 1250, Text: "a"},
+			},
+		},
+		{
+			name: "enhanced line",
+			text: "[00:12.50]<00:12.50>You <00:13.10>set <00:13.60>my <00:14.00>soul <00:15.20>",
+			want: model.TimedLines{
+				{
+					Index: 0, Start: 12500, Text: "You set my soul", End: int64Ptr(15200),
+					Words: model.TimedWords{
+						{Start: 12500, Text: "You "},
+						{Start: 13100, Text: "set "},
+						{Start: 13600, Text: "my "},
+						{Start: 14000, Text: "soul"},
+					},
+				},
+			},
+		},
+		{
+			name: "text before first word timestamp starts with line",
+			text: "[00:01.00]Oh <00:01.50>yeah",
+			want: model.TimedLines{
+				{
+					Index: 0, Start: 1000, Text: "Oh yeah",
+					Words: model.TimedWords{{Start: 1000, Text: "Oh "}, {Start: 1500, Text: "yeah"}},
+				},
+			},
+		},
+		{
+			name: "line timestamp goes backward",
+			text: "[00:02.00]b\n[00:01.00]a",
+			err:  domain.ErrTimestampOrder,
+		},
+		{
+			name: "line goes backward after compressed line",
+			text: "[00:10.00][00:30.00]Chorus\n[00:20.00]Verse\n[00:05.00]Intro",
+			err:  domain.ErrTimestampOrder,
+		},
+		{
+			name: "word timestamp goes backward",
+			text: "[00:05.00]<00:05.00>a <00:04.00>b",
+			err:  domain.ErrTimestampOrder,
+		},
+		{
+			name: "word timestamp precedes line",
+			text: "[00:05.00]<00:04.00>a",
+			err:  domain.ErrTimestampOrder,
+		},
+		{
+			name: "line without timestamp",
+			text: "[00:01.00]a\nplain text",
+			err:  domain.ErrInvalidLRC,
+		},
+		{
+			name: "invalid offset",
+			text: "[offset:soon]\n[00:01.00]a",
+			err:  domain.ErrInvalidLRC,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLRC(tt.text)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseLRC() =\n%s\nwant\n%s", formatTimedLines(got), formatTimedLines(tt.want))
+			}
+		})
+	}
+}
+
+func TestFormatLRC(t *testing.T) {
+	lines := model.TimedLines{
+		{Index: 0, Start: 1234, Text: "Plain"},
+		{
+			Index: 1, Start: 62500, Text: "You set", End: int64Ptr(64000),
+			Words: model.TimedWords{{Start: 62500, Text: "You "}, {Start: 63100, Text: "set"}},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		title    string
+		artist   string
+		enhanced bool
+		want     string
+	}{
+		{
+			name:   "plain",
+			title:  "Song",
+			artist: "Artist",
+			want:   "[ti:Song]\n[ar:Artist]\n[00:01.234]Plain\n[01:02.50]You set\n",
+		},
+		{
+			name:     "enhanced",
+			enhanced: true,
+			want:     "[00:01.234]Plain\n[01:02.50]<01:02.50>You <01:03.10>set <01:04.00>\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatLRC(tt.title, tt.artist, lines, tt.enhanced)
+
+			if got != tt.want {
+				t.Errorf("FormatLRC() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLRCRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		enhanced bool
+	}{
+		{
+			name: "plain",
+			text: "[ti:Song]\n[00:01.00]Hello\n[00:02.345]World\n[60:00.00]End\n",
+		},
+		{
+			name:     "enhanced",
+			text:     "[00:12.50]<00:12.50>You <00:13.10>set <00:13.60>my <00:14.00>soul <00:15.20>\n[00:16.00]<00:16.00>Alight\n",
+			enhanced: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, err := ParseLRC(tt.text)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+
+			formatted := FormatLRC("", "", lines, tt.enhanced)
+
+			reparsed, err := ParseLRC(formatted)
+			if err != nil {
+				t.Fatalf("parse formatted: %v", err)
+			}
+
+			if !reflect.DeepEqual(reparsed, lines) {
+				t.Errorf("round trip =\n%s\nwant\n%s", formatTimedLines(reparsed), formatTimedLines(lines))
+			}
+		})
+	}
+}
+
+func formatTimedLines(lines model.TimedLines) string {
+	data, _ := json.MarshalIndent(lines, "", "  ")
+
+	return string(data)
+}
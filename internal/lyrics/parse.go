@@ -0,0 +1,204 @@
+// Splits song lyrics into typed sections
+package lyrics
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+// Line breaks in any form, escaped ones included
+var lineBreaks = strings.NewReplacer("\r\n", "\n", "\r", "\n", `\r\n`, "\n", `\n`, "\n")
+
+// Section header such as [Chorus], (Verse 2) or Bridge:
+var headerPattern = regexp.MustCompile(
+	`(?i)^[\[(]?\s*(verse|chorus|refrain|hook|pre-?chorus|bridge|intro|outro)\s*(\d*)\s*[\])]?\s*:?$`,
+)
+
+// Header words and section types they mark
+var headerTypes = map[string]model.SectionType{
+	"verse":      model.SectionVerse,
+	"chorus":     model.SectionChorus,
+	"refrain":    model.SectionChorus,
+	"hook":       model.SectionChorus,
+	"prechorus":  model.SectionBridge,
+	"pre-chorus": model.SectionBridge,
+	"bridge":     model.SectionBridge,
+	"intro":      model.SectionIntro,
+	"outro":      model.SectionOutro,
+}
+
+// Lines of lyrics between blank lines with optional header
+type block struct {
+	header *header
+	lines  []string
+}
+
+type header struct {
+	sectionType model.SectionType
+	label       string
+}
+
+// Unifies line breaks and trims spaces around lines and text
+func Normalize(text string) string {
+	lines := strings.Split(lineBreaks.Replace(text), "\n")
+
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(line)
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+// Splits lyrics into sections separated by one or more blank lines.
+// Sections are typed by their headers, unmarked sections repeated in lyrics are treated as chorus
+// and the rest as verses. Repeated sections refer to their first occurrence
+func Parse(text string) model.Sections {
+	blocks := splitBlocks(Normalize(text))
+	sections := make(model.Sections, 0, len(blocks))
+
+	// Sections are matched by lines ignoring case and spaces
+	keys := make([]string, len(blocks))
+	occurrences := make(map[string]int)
+	first := make(map[string]int)
+
+	for i, b := range blocks {
+		keys[i] = blockKey(b.lines)
+		occurrences[keys[i]]++
+
+		if _, ok := first[keys[i]]; !ok {
+			first[keys[i]] = i
+		}
+	}
+
+	verses := 0
+
+	for i, b := range blocks {
+		section := &model.Section{
+			Index: i,
+			Lines: b.lines,
+		}
+
+		firstIndex := first[keys[i]]
+
+		switch {
+		case b.header != nil:
+			section.Type = b.header.sectionType
+			section.Label = b.header.label
+
+			if section.Type == model.SectionVerse {
+				verses++
+			}
+		case firstIndex < i:
+			section.Type = sections[firstIndex].Type
+			section.Label = sections[firstIndex].Label
+		case occurrences[keys[i]] > 1:
+			section.Type = model.SectionChorus
+			section.Label = "Chorus"
+		default:
+			verses++
+			section.Type = model.SectionVerse
+			section.Label = "Verse " + strconv.Itoa(verses)
+		}
+
+		if firstIndex < i {
+			section.RepeatOf = &firstIndex
+		}
+
+		sections = append(sections, section)
+	}
+
+	return sections
+}
+
+// Groups lines into blocks, header lines start new block.
+// Header without lines marks following unmarked block or repeats last section with same label
+func splitBlocks(text string) []block {
+	var (
+		raw     []block
+		current block
+	)
+
+	flush := func() {
+		if current.header != nil || len(current.lines) > 0 {
+			raw = append(raw, current)
+		}
+
+		current = block{}
+	}
+
+	for _, line := range strings.Split(text, "\n") {
+		if len(line) == 0 {
+			flush()
+			continue
+		}
+
+		if h := parseHeader(line); h != nil {
+			flush()
+			current.header = h
+
+			continue
+		}
+
+		current.lines = append(current.lines, line)
+	}
+
+	flush()
+
+	blocks := make([]block, 0, len(raw))
+
+	for i := 0; i < len(raw); i++ {
+		b := raw[i]
+
+		if len(b.lines) > 0 {
+			blocks = append(blocks, b)
+			continue
+		}
+
+		if i+1 < len(raw) && raw[i+1].header == nil {
+			raw[i+1].header = b.header
+			continue
+		}
+
+		if repeated, ok := lastWithLabel(blocks, b.header.label); ok {
+			blocks = append(blocks, block{header: b.header, lines: repeated.lines})
+		}
+	}
+
+	return blocks
+}
+
+func lastWithLabel(blocks []block, label string) (block, bool) {
+	for i := len(blocks) - 1; i >= 0; i-- {
+		if blocks[i].header != nil && strings.EqualFold(blocks[i].header.label, label) {
+			return blocks[i], true
+		}
+	}
+
+	return block{}, false
+}
+
+func parseHeader(line string) *header {
+	match := headerPattern.FindStringSubmatch(line)
+	if match == nil {
+		return nil
+	}
+
+	word := strings.ToLower(match[1])
+
+	label := strings.ToUpper(word[:1]) + word[1:]
+	if len(match[2]) > 0 {
+		label += " " + match[2]
+	}
+
+	return &header{
+		sectionType: headerTypes[word],
+		label:       label,
+	}
+}
+
+func blockKey(lines []string) string {
+	return strings.ToLower(strings.Join(strings.Fields(strings.Join(lines, " ")), " "))
+}
@@ -0,0 +1,146 @@
+package lyrics
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "plain", text: "a\nb", want: "a\nb"},
+		{name: "windows line breaks", text: "a\r\nb\rc", want: "a\nb\nc"},
+		{name: "escaped line breaks", text: `a\nb\r\nc`, want: "a\nb\nc"},
+		{name: "spaces around lines", text: "  a  \n\tb \n", want: "a\nb"},
+		{name: "blank lines around text", text: "\n\n a\n\n\nb \n\n", want: "a\n\n\nb"},
+		{name: "spaces inside line are kept", text: "a   b", want: "a   b"},
+		{name: "empty", text: " \n ", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.text); got != tt.want {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want model.Sections
+	}{
+		{
+			name: "empty",
+			text: "",
+			want: model.Sections{},
+		},
+		{
+			name: "unmarked verses are numbered",
+			text: "a\n\nb\n\n\nc",
+			want: model.Sections{
+				{Index: 0, Type: model.SectionVerse, Label: "Verse 1", Lines: model.Lines{"a"}},
+				{Index: 1, Type: model.SectionVerse, Label: "Verse 2", Lines: model.Lines{"b"}},
+				{Index: 2, Type: model.SectionVerse, Label: "Verse 3", Lines: model.Lines{"c"}},
+			},
+		},
+		{
+			name: "repeated unmarked section is chorus",
+			text: "Hello World\nAgain\n\nmiddle\n\nhello   world\nagain",
+			want: model.Sections{
+				{Index: 0, Type: model.SectionChorus, Label: "Chorus", Lines: model.Lines{"Hello World", "Again"}},
+				{Index: 1, Type: model.SectionVerse, Label: "Verse 1", Lines: model.Lines{"middle"}},
+				{Index: 2, Type: model.SectionChorus, Label: "Chorus", Lines: model.Lines{"hello   world", "again"}, RepeatOf: intPtr(0)},
+			},
+		},
+		{
+			name: "headers",
+			text: "[Intro]\no\n\n[Verse 1]\nx\n\n(Pre-chorus)\np\n\nREFRAIN:\ny\n\nbridge 2\nz\n\n[Outro]\nw",
+			want: model.Sections{
+				{Index: 0, Type: model.SectionIntro, Label: "Intro", Lines: model.Lines{"o"}},
+				{Index: 1, Type: model.SectionVerse, Label: "Verse 1", Lines: model.Lines{"x"}},
+				{Index: 2, Type: model.SectionBridge, Label: "Pre-chorus", Lines: model.Lines{"p"}},
+				{Index: 3, Type: model.SectionChorus, Label: "Refrain", Lines: model.Lines{"y"}},
+				{Index: 4, Type: model.SectionBridge, Label: "Bridge 2", Lines: model.Lines{"z"}},
+				{Index: 5, Type: model.SectionOutro, Label: "Outro", Lines: model.Lines{"w"}},
+			},
+		},
+		{
+			name: "header starts block without blank line",
+			text: "x\n[Chorus]\ny",
+			want: model.Sections{
+				{Index: 0, Type: model.SectionVerse, Label: "Verse 1", Lines: model.Lines{"x"}},
+				{Index: 1, Type: model.SectionChorus, Label: "Chorus", Lines: model.Lines{"y"}},
+			},
+		},
+		{
+			name: "header marks following block",
+			text: "[Chorus]\n\ny",
+			want: model.Sections{
+				{Index: 0, Type: model.SectionChorus, Label: "Chorus", Lines: model.Lines{"y"}},
+			},
+		},
+		{
+			name: "lone header repeats section",
+			text: "[Verse 1]\nx\n\n[Chorus]\ny\n\n[Chorus]\n\n(Bridge)\nz",
+			want: model.Sections{
+				{Index: 0, Type: model.SectionVerse, Label: "Verse 1", Lines: model.Lines{"x"}},
+				{Index: 1, Type: model.SectionChorus, Label: "Chorus", Lines: model.Lines{"y"}},
+				{Index: 2, Type: model.SectionChorus, Label: "Chorus", Lines: model.Lines{"y"}, RepeatOf: intPtr(1)},
+				{Index: 3, Type: model.SectionBridge, Label: "Bridge", Lines: model.Lines{"z"}},
+			},
+		},
+		{
+			name: "lone header without earlier section is dropped",
+			text: "x\n\n[Chorus]\n\n[Bridge]\nz",
+			want: model.Sections{
+				{Index: 0, Type: model.SectionVerse, Label: "Verse 1", Lines: model.Lines{"x"}},
+				{Index: 1, Type: model.SectionBridge, Label: "Bridge", Lines: model.Lines{"z"}},
+			},
+		},
+		{
+			name: "repeat keeps type of first occurrence",
+			text: "[Bridge]\nz\n\nx\n\nz",
+			want: model.Sections{
+				{Index: 0, Type: model.SectionBridge, Label: "Bridge", Lines: model.Lines{"z"}},
+				{Index: 1, Type: model.SectionVerse, Label: "Verse 1", Lines: model.Lines{"x"}},
+				{Index: 2, Type: model.SectionBridge, Label: "Bridge", Lines: model.Lines{"z"}, RepeatOf: intPtr(0)},
+			},
+		},
+		{
+			name: "header like words inside line",
+			text: "verse of the song",
+			want: model.Sections{
+				{Index: 0, Type: model.SectionVerse, Label: "Verse 1", Lines: model.Lines{"verse of the song"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Parse(tt.text)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse(%q) =\n%s\nwant\n%s", tt.text, formatSections(got), formatSections(tt.want))
+			}
+		})
+	}
+}
+
+func formatSections(sections model.Sections) string {
+	data, _ := json.MarshalIndent(sections, "", "  ")
+
+	return string(data)
+}
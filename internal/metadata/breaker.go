@@ -0,0 +1,170 @@
+package metadata
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type BreakerState int
+
+const (
+	// Requests pass through
+	BreakerClosed BreakerState = iota
+	// Requests fail fast until cooldown passes
+	BreakerOpen
+	// Single trial request is allowed to check if service is back
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	}
+
+	return "unknown"
+}
+
+// Returned instead of request while circuit breaker is open
+type CircuitOpenError struct {
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker is open, retry after %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrUnavailable
+}
+
+// Snapshot of circuit breaker
+type BreakerStatus struct {
+	State      string  `json:"state" example:"closed"`
+	Failures   uint    `json:"failures" example:"0"`
+	RetryAfter float64 `json:"retry_after_seconds" example:"0"`
+}
+
+// Stops calling failing service after threshold of consecutive failures
+type CircuitBreaker struct {
+	name      string
+	threshold uint
+	cooldown  time.Duration
+	log       *zap.SugaredLogger
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures uint
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreaker(name string, threshold uint, cooldown time.Duration, log *zap.SugaredLogger) *CircuitBreaker {
+	if threshold == 0 {
+		threshold = 1
+	}
+
+	return &CircuitBreaker{
+		name:      name,
+		threshold: threshold,
+		cooldown:  cooldown,
+		log:       log,
+	}
+}
+
+// Checks whether request may be performed, returns *CircuitOpenError otherwise
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		elapsed := time.Since(b.openedAt)
+		if elapsed < b.cooldown {
+			return &CircuitOpenError{RetryAfter: b.cooldown - elapsed}
+		}
+
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+	case BreakerHalfOpen:
+		// Only one trial request at a time
+		if b.probing {
+			return &CircuitOpenError{RetryAfter: b.cooldown}
+		}
+
+		b.probing = true
+	}
+
+	return nil
+}
+
+// Records successful request
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.probing = false
+
+	if b.state != BreakerClosed {
+		b.setState(BreakerClosed)
+	}
+}
+
+// Records failed request
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.probing = false
+
+	if b.state == BreakerHalfOpen || b.failures >= b.threshold {
+		b.openedAt = time.Now()
+
+		if b.state != BreakerOpen {
+			b.setState(BreakerOpen)
+		}
+	}
+}
+
+// Releases trial request which ended without result, e.g. was cancelled by client
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
+
+func (b *CircuitBreaker) Status() BreakerStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BreakerStatus{
+		State:    b.state.String(),
+		Failures: b.failures,
+	}
+
+	if b.state == BreakerOpen {
+		retryAfter := b.cooldown - time.Since(b.openedAt)
+		if retryAfter > 0 {
+			status.RetryAfter = retryAfter.Seconds()
+		}
+	}
+
+	return status
+}
+
+// Must be called with mutex held
+func (b *CircuitBreaker) setState(state BreakerState) {
+	b.log.Warnf("%s circuit breaker state changed: %s -> %s (failures: %d)", b.name, b.state, state, b.failures)
+
+	b.state = state
+}
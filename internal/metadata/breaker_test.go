@@ -0,0 +1,201 @@
+package metadata
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const testCooldown = time.Minute
+
+// Moves opening time of breaker back as if cooldown has passed
+func expireCooldown(b *CircuitBreaker) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.openedAt = time.Now().Add(-testCooldown)
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	type step struct {
+		// One of allow, success, failure, release, expire
+		action string
+		// Allow must be rejected
+		open bool
+		// State after action
+		state BreakerState
+	}
+
+	tests := []struct {
+		name      string
+		threshold uint
+		steps     []step
+	}{
+		{
+			name:      "failures below threshold",
+			threshold: 3,
+			steps: []step{
+				{action: "allow", state: BreakerClosed},
+				{action: "failure", state: BreakerClosed},
+				{action: "failure", state: BreakerClosed},
+				{action: "allow", state: BreakerClosed},
+			},
+		},
+		{
+			name:      "success resets failures",
+			threshold: 2,
+			steps: []step{
+				{action: "failure", state: BreakerClosed},
+				{action: "success", state: BreakerClosed},
+				{action: "failure", state: BreakerClosed},
+				{action: "allow", state: BreakerClosed},
+			},
+		},
+		{
+			name:      "opens on threshold",
+			threshold: 2,
+			steps: []step{
+				{action: "failure", state: BreakerClosed},
+				{action: "failure", state: BreakerOpen},
+				{action: "allow", open: true, state: BreakerOpen},
+			},
+		},
+		{
+			name:      "zero threshold opens on first failure",
+			threshold: 0,
+			steps: []step{
+				{action: "failure", state: BreakerOpen},
+				{action: "allow", open: true, state: BreakerOpen},
+			},
+		},
+		{
+			name:      "successful probe closes",
+			threshold: 1,
+			steps: []step{
+				{action: "failure", state: BreakerOpen},
+				{action: "expire", state: BreakerOpen},
+				{action: "allow", state: BreakerHalfOpen},
+				{action: "success", state: BreakerClosed},
+				{action: "allow", state: BreakerClosed},
+			},
+		},
+		{
+			name:      "failed probe opens again",
+			threshold: 3,
+			steps: []step{
+				{action: "failure", state: BreakerClosed},
+				{action: "failure", state: BreakerClosed},
+				{action: "failure", state: BreakerOpen},
+				{action: "expire", state: BreakerOpen},
+				{action: "allow", state: BreakerHalfOpen},
+				{action: "failure", state: BreakerOpen},
+				{action: "allow", open: true, state: BreakerOpen},
+			},
+		},
+		{
+			name:      "single probe at a time",
+			threshold: 1,
+			steps: []step{
+				{action: "failure", state: BreakerOpen},
+				{action: "expire", state: BreakerOpen},
+				{action: "allow", state: BreakerHalfOpen},
+				{action: "allow", open: true, state: BreakerHalfOpen},
+			},
+		},
+		{
+			name:      "released probe lets next request through",
+			threshold: 1,
+			steps: []step{
+				{action: "failure", state: BreakerOpen},
+				{action: "expire", state: BreakerOpen},
+				{action: "allow", state: BreakerHalfOpen},
+				{action: "release", state: BreakerHalfOpen},
+				{action: "allow", state: BreakerHalfOpen},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewCircuitBreaker("test", tt.threshold, testCooldown, zap.NewNop().Sugar())
+
+			for i, s := range tt.steps {
+				switch s.action {
+				case "allow":
+					err := b.Allow()
+
+					var openErr *CircuitOpenError
+					if got := errors.As(err, &openErr); got != s.open {
+						t.Fatalf("step %d: allow error = %v, want open %v", i, err, s.open)
+					}
+				case "success":
+					b.Success()
+				case "failure":
+					b.Failure()
+				case "release":
+					b.Release()
+				case "expire":
+					expireCooldown(b)
+				default:
+					t.Fatalf("step %d: unknown action %q", i, s.action)
+				}
+
+				if b.state != s.state {
+					t.Fatalf("step %d (%s): state = %s, want %s", i, s.action, b.state, s.state)
+				}
+			}
+		})
+	}
+}
+
+func TestCircuitOpenError(t *testing.T) {
+	b := NewCircuitBreaker("test", 1, testCooldown, zap.NewNop().Sugar())
+	b.Failure()
+
+	err := b.Allow()
+
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("error = %v, want *CircuitOpenError", err)
+	}
+
+	if openErr.RetryAfter <= 0 || openErr.RetryAfter > testCooldown {
+		t.Errorf("retry after = %s, want within (0, %s]", openErr.RetryAfter, testCooldown)
+	}
+
+	if !errors.Is(err, ErrUnavailable) {
+		t.Errorf("open breaker error must wrap %v", ErrUnavailable)
+	}
+}
+
+func TestCircuitBreakerStatus(t *testing.T) {
+	b := NewCircuitBreaker("test", 2, testCooldown, zap.NewNop().Sugar())
+
+	b.Failure()
+
+	status := b.Status()
+	if status.State != "closed" || status.Failures != 1 || status.RetryAfter != 0 {
+		t.Errorf("status after failure = %+v", status)
+	}
+
+	b.Failure()
+
+	status = b.Status()
+	if status.State != "open" || status.Failures != 2 {
+		t.Errorf("status after opening = %+v", status)
+	}
+
+	if status.RetryAfter <= 0 || status.RetryAfter > testCooldown.Seconds() {
+		t.Errorf("retry after = %v seconds, want within (0, %v]", status.RetryAfter, testCooldown.Seconds())
+	}
+
+	// Cooldown is over but no request was made yet
+	expireCooldown(b)
+
+	status = b.Status()
+	if status.State != "open" || status.RetryAfter != 0 {
+		t.Errorf("status after cooldown = %+v", status)
+	}
+}
@@ -0,0 +1,143 @@
+package metadata
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Sadere/song-depository/internal/config"
+	"github.com/go-resty/resty/v2"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+const releaseDateLayout = "02.01.2006"
+
+// Response of music info service
+type infoResponse struct {
+	ReleaseDate string `json:"releaseDate"`
+	Text        string `json:"text"`
+	Link        string `json:"link"`
+}
+
+// Provider requesting music info HTTP service
+type HTTPProvider struct {
+	client  *resty.Client
+	breaker *CircuitBreaker
+	log     *zap.SugaredLogger
+}
+
+func NewHTTPProvider(cfg *config.Config, log *zap.SugaredLogger) *HTTPProvider {
+	// Retries are performed with jittered exponential backoff between wait and max wait
+	client := resty.New().
+		SetBaseURL(cfg.MusicInfoAddress).
+		SetTimeout(cfg.MusicInfoTimeout).
+		SetRetryCount(int(cfg.MusicInfoRetryCount)).
+		SetRetryWaitTime(cfg.MusicInfoRetryWait).
+		SetRetryMaxWaitTime(cfg.MusicInfoRetryMaxWait).
+		AddRetryCondition(func(r *resty.Response, err error) bool {
+			// Network errors, throttling and server side failures
+			return err != nil || (r != nil && isTransientStatus(r.StatusCode()))
+		}).
+		AddRetryHook(func(r *resty.Response, err error) {
+			if err != nil {
+				log.Warn("music info request attempt failed: ", err)
+				return
+			}
+
+			log.Warn("music info request attempt failed with status: ", r.StatusCode())
+		})
+
+	return &HTTPProvider{
+		client:  client,
+		breaker: NewCircuitBreaker("music info", cfg.MusicInfoBreakerThreshold, cfg.MusicInfoBreakerCooldown, log),
+		log:     log,
+	}
+}
+
+// Returns state of music info circuit breaker
+func (p *HTTPProvider) BreakerStatus() BreakerStatus {
+	return p.breaker.Status()
+}
+
+func (p *HTTPProvider) Lookup(ctx context.Context, group, name string) (SongDetail, error) {
+	// Fail fast while music info service is down
+	err := p.breaker.Allow()
+	if err != nil {
+		return SongDetail{}, err
+	}
+
+	p.log.Debug("music info endpoint request, group: ", group, " song: ", name)
+
+	response, err := p.client.R().
+		SetContext(ctx).
+		SetQueryParams(map[string]string{
+			"group": group,
+			"song":  name,
+		}).
+		Get("/info")
+
+	if err != nil {
+		// Cancelled by caller, service state is unknown
+		if ctx.Err() != nil {
+			p.breaker.Release()
+			return SongDetail{}, errors.Wrap(ctx.Err(), "music info request")
+		}
+
+		p.breaker.Failure()
+		return SongDetail{}, errors.Wrap(ErrUnavailable, err.Error())
+	}
+
+	p.log.Debug("music info endpoint response: ", response.Status(), " body: ", response.String())
+
+	if isTransientStatus(response.StatusCode()) {
+		p.breaker.Failure()
+		return SongDetail{}, errors.Wrapf(ErrUnavailable, "unexpected status %d", response.StatusCode())
+	}
+
+	// Service is responding
+	p.breaker.Success()
+
+	switch {
+	case response.StatusCode() == http.StatusNotFound:
+		return SongDetail{}, ErrNotFound
+	case response.StatusCode() >= http.StatusBadRequest:
+		// Retrying same request won't help
+		return SongDetail{}, errors.Wrapf(ErrRejected, "unexpected status %d", response.StatusCode())
+	case response.StatusCode() != http.StatusOK:
+		return SongDetail{}, errors.Wrapf(ErrUnavailable, "unexpected status %d", response.StatusCode())
+	}
+
+	return parseInfoResponse(response.Body())
+}
+
+// Reports whether status means service may answer on later attempt
+func isTransientStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// Converts music info response body to song detail
+func parseInfoResponse(body []byte) (SongDetail, error) {
+	var info infoResponse
+
+	err := json.Unmarshal(body, &info)
+	if err != nil {
+		return SongDetail{}, errors.Wrap(ErrMalformed, err.Error())
+	}
+
+	detail := SongDetail{
+		Text: info.Text,
+		Link: info.Link,
+	}
+
+	// Date may be unknown to service
+	if len(info.ReleaseDate) > 0 {
+		detail.ReleaseDate, err = time.Parse(releaseDateLayout, info.ReleaseDate)
+		if err != nil {
+			return SongDetail{}, errors.Wrap(ErrMalformed, err.Error())
+		}
+	}
+
+	return detail, nil
+}
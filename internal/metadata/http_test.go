@@ -0,0 +1,216 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Sadere/song-depository/internal/config"
+	"go.uber.org/zap"
+)
+
+// Starts music info service answering every request with given status and body
+func newInfoServer(t *testing.T, status int, body string) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+
+	var calls atomic.Int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		if r.URL.Path != "/info" || r.URL.Query().Get("group") != "Muse" || r.URL.Query().Get("song") != "Uprising" {
+			w.WriteHeader(http.StatusTeapot)
+			return
+		}
+
+		w.WriteHeader(status)
+		w.Write([]byte(body)) //nolint:errcheck
+	}))
+	t.Cleanup(srv.Close)
+
+	return srv, &calls
+}
+
+func newTestProvider(address string, threshold uint) *HTTPProvider {
+	cfg := &config.Config{
+		MusicInfoAddress:          address,
+		MusicInfoTimeout:          time.Second,
+		MusicInfoRetryCount:       2,
+		MusicInfoRetryWait:        time.Millisecond,
+		MusicInfoRetryMaxWait:     time.Millisecond,
+		MusicInfoBreakerThreshold: threshold,
+		MusicInfoBreakerCooldown:  time.Minute,
+	}
+
+	return NewHTTPProvider(cfg, zap.NewNop().Sugar())
+}
+
+func TestHTTPProviderLookup(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		want     SongDetail
+		wantErr  error
+		attempts int32
+	}{
+		{
+			name:     "found",
+			status:   http.StatusOK,
+			body:     `{"releaseDate":"16.07.2006","text":"Ooh baby","link":"https://example.com"}`,
+			want:     SongDetail{ReleaseDate: time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC), Text: "Ooh baby", Link: "https://example.com"},
+			attempts: 1,
+		},
+		{
+			name:     "unknown release date",
+			status:   http.StatusOK,
+			body:     `{"releaseDate":"","text":"Paranoia","link":""}`,
+			want:     SongDetail{Text: "Paranoia"},
+			attempts: 1,
+		},
+		{
+			name:     "not found",
+			status:   http.StatusNotFound,
+			wantErr:  ErrNotFound,
+			attempts: 1,
+		},
+		{
+			name:     "rejected request is not retried",
+			status:   http.StatusBadRequest,
+			wantErr:  ErrRejected,
+			attempts: 1,
+		},
+		{
+			name:     "throttled request is retried",
+			status:   http.StatusTooManyRequests,
+			wantErr:  ErrUnavailable,
+			attempts: 3,
+		},
+		{
+			name:     "server error is retried",
+			status:   http.StatusBadGateway,
+			wantErr:  ErrUnavailable,
+			attempts: 3,
+		},
+		{
+			name:     "not json",
+			status:   http.StatusOK,
+			body:     `<html></html>`,
+			wantErr:  ErrMalformed,
+			attempts: 1,
+		},
+		{
+			name:     "invalid release date",
+			status:   http.StatusOK,
+			body:     `{"releaseDate":"2006-07-16","text":"","link":""}`,
+			wantErr:  ErrMalformed,
+			attempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv, calls := newInfoServer(t, tt.status, tt.body)
+			p := newTestProvider(srv.URL, 10)
+
+			got, err := p.Lookup(context.Background(), "Muse", "Uprising")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("detail = %+v, want %+v", got, tt.want)
+			}
+
+			if calls.Load() != tt.attempts {
+				t.Errorf("attempts = %d, want %d", calls.Load(), tt.attempts)
+			}
+		})
+	}
+}
+
+func TestHTTPProviderOpensBreaker(t *testing.T) {
+	srv, calls := newInfoServer(t, http.StatusServiceUnavailable, "")
+	p := newTestProvider(srv.URL, 1)
+
+	_, err := p.Lookup(context.Background(), "Muse", "Uprising")
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("first lookup error = %v, want %v", err, ErrUnavailable)
+	}
+
+	attempts := calls.Load()
+
+	_, err = p.Lookup(context.Background(), "Muse", "Uprising")
+
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) {
+		t.Fatalf("second lookup error = %v, want *CircuitOpenError", err)
+	}
+
+	if calls.Load() != attempts {
+		t.Errorf("service was called while breaker is open")
+	}
+
+	if status := p.BreakerStatus(); status.State != "open" {
+		t.Errorf("breaker state = %s, want open", status.State)
+	}
+}
+
+func TestHTTPProviderRejectedKeepsBreakerClosed(t *testing.T) {
+	srv, calls := newInfoServer(t, http.StatusUnprocessableEntity, "")
+	p := newTestProvider(srv.URL, 1)
+
+	for i := 0; i < 3; i++ {
+		_, err := p.Lookup(context.Background(), "Muse", "Uprising")
+		if !errors.Is(err, ErrRejected) {
+			t.Fatalf("lookup %d error = %v, want %v", i, err, ErrRejected)
+		}
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("attempts = %d, want 3", calls.Load())
+	}
+
+	if status := p.BreakerStatus(); status.State != "closed" || status.Failures != 0 {
+		t.Errorf("breaker status = %+v, want closed without failures", status)
+	}
+}
+
+func TestHTTPProviderUnreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	p := newTestProvider(srv.URL, 1)
+
+	_, err := p.Lookup(context.Background(), "Muse", "Uprising")
+	if !errors.Is(err, ErrUnavailable) {
+		t.Fatalf("error = %v, want %v", err, ErrUnavailable)
+	}
+
+	if status := p.BreakerStatus(); status.State != "open" {
+		t.Errorf("breaker state = %s, want open", status.State)
+	}
+}
+
+func TestHTTPProviderCancelled(t *testing.T) {
+	srv, _ := newInfoServer(t, http.StatusOK, `{}`)
+	p := newTestProvider(srv.URL, 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := p.Lookup(ctx, "Muse", "Uprising")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("error = %v, want %v", err, context.Canceled)
+	}
+
+	// Cancelled request says nothing about service
+	if status := p.BreakerStatus(); status.State != "closed" || status.Failures != 0 {
+		t.Errorf("breaker status = %+v, want closed without failures", status)
+	}
+}
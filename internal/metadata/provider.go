@@ -0,0 +1,28 @@
+// Provides song details from external sources
+package metadata
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrNotFound    = errors.New("song detail not found")
+	ErrUnavailable = errors.New("song detail provider is unavailable")
+	ErrMalformed   = errors.New("song detail provider returned malformed response")
+	ErrRejected    = errors.New("song detail provider rejected request")
+)
+
+// Song details found by provider, zero release date means it's unknown
+type SongDetail struct {
+	ReleaseDate time.Time
+	Text        string
+	Link        string
+}
+
+// Source of song details
+type MetadataProvider interface {
+	// Looks up details of song by its group and name
+	Lookup(ctx context.Context, group, name string) (SongDetail, error)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Allows requests with X-Admin-Token header matching token, empty token disables route
+func AdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if len(token) == 0 {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+				"error": "admin routes are disabled",
+			})
+			return
+		}
+
+		if subtle.ConstantTimeCompare([]byte(c.GetHeader("X-Admin-Token")), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+				"error": "invalid admin token",
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
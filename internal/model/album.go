@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// Kind of release
+type AlbumType string
+
+const (
+	AlbumLP          AlbumType = "lp"
+	AlbumEP          AlbumType = "ep"
+	AlbumSingle      AlbumType = "single"
+	AlbumCompilation AlbumType = "compilation"
+)
+
+type Album struct {
+	ID          uint64     `db:"id" json:"id" example:"1"`
+	CreatedAt   time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time  `db:"updated_at" json:"updated_at"`
+	Title       string     `db:"title" json:"title" example:"Black Holes and Revelations"`
+	ArtistID    uint64     `db:"artist_id" json:"artist_id" example:"1"`
+	Artist      string     `db:"artist_name" json:"artist" example:"Muse"`
+	ReleaseDate *time.Time `db:"release_date" json:"release_date" example:"2006-07-03T00:00:00Z"`
+	Type        AlbumType  `db:"album_type" json:"type" example:"lp"`
+	CoverLink   string     `db:"cover_link" json:"cover_link" example:"https://example.com/cover.jpg"`
+	TrackCount  uint64     `db:"track_count" json:"track_count" example:"11"`
+
+	// Track listing ordered by disc and track number, only filled for single album
+	Tracks []Track `db:"-" json:"tracks,omitempty"`
+}
+
+type Albums []*Album
+
+// Song position on album
+type Track struct {
+	SongID      uint64 `db:"song_id" json:"song_id" example:"1"`
+	Name        string `db:"song_name" json:"song" example:"Supermassive Black Hole"`
+	Group       string `db:"song_group" json:"group" example:"Muse"`
+	DiscNumber  uint   `db:"disc_number" json:"disc_number" example:"1"`
+	TrackNumber uint   `db:"track_number" json:"track_number" example:"2"`
+}
@@ -0,0 +1,38 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type Artist struct {
+	ID        uint64    `db:"id" json:"id" example:"1"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time `db:"updated_at" json:"updated_at"`
+	Name      string    `db:"name" json:"name" example:"The Beatles"`
+	Slug      string    `db:"slug" json:"slug" example:"the-beatles"`
+
+	// Names songs are resolved to artist by, canonical name included
+	Aliases   Aliases `db:"aliases" json:"aliases" example:"The Beatles,Beatles"`
+	SongCount uint64  `db:"song_count" json:"song_count" example:"12"`
+}
+
+type Artists []*Artist
+
+// Artist names, stored as JSON array
+type Aliases []string
+
+func (a *Aliases) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, a)
+	case string:
+		return json.Unmarshal([]byte(v), a)
+	}
+
+	return fmt.Errorf("unsupported aliases source type %T", src)
+}
@@ -0,0 +1,44 @@
+package model
+
+// Kind of chord sheet section
+type ChordSectionType string
+
+const (
+	ChordVerse  ChordSectionType = "verse"
+	ChordChorus ChordSectionType = "chorus"
+	ChordBridge ChordSectionType = "bridge"
+	ChordTab    ChordSectionType = "tab"
+)
+
+// Song lyrics with chords parsed from ChordPro
+type ChordSheet struct {
+	Title    string `json:"title,omitempty" example:"Amazing Grace"`
+	Subtitle string `json:"subtitle,omitempty" example:"Traditional"`
+	Key      string `json:"key,omitempty" example:"G"`
+	Capo     int    `json:"capo,omitempty" example:"2"`
+
+	// Other metadata directives such as artist, tempo or time
+	Meta     map[string]string `json:"meta,omitempty"`
+	Sections []ChordSection    `json:"sections"`
+}
+
+type ChordSection struct {
+	Type  ChordSectionType `json:"type" example:"chorus"`
+	Label string           `json:"label,omitempty" example:"Chorus"`
+
+	// Chorus is repeated here, its lines are those of last chorus
+	Repeat bool        `json:"repeat,omitempty"`
+	Lines  []ChordLine `json:"lines,omitempty"`
+}
+
+// Line of chord sheet, either comment or lyrics split at chord changes
+type ChordLine struct {
+	Comment  string         `json:"comment,omitempty" example:"Slowly"`
+	Segments []ChordSegment `json:"segments,omitempty"`
+}
+
+// Lyrics sung from chord change until next one
+type ChordSegment struct {
+	Chord  string `json:"chord,omitempty" example:"G/B"`
+	Lyrics string `json:"lyrics" example:"Amazing "`
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Part artist took in song
+type CreditRole string
+
+const (
+	CreditPrimary   CreditRole = "primary"
+	CreditFeaturing CreditRole = "featuring"
+	CreditComposer  CreditRole = "composer"
+	CreditLyricist  CreditRole = "lyricist"
+	CreditProducer  CreditRole = "producer"
+)
+
+// Credit roles in listing order
+var CreditRoles = []CreditRole{CreditPrimary, CreditFeaturing, CreditComposer, CreditLyricist, CreditProducer}
+
+// Artist credited on song
+type Credit struct {
+	ArtistID uint64     `json:"artist_id" example:"2"`
+	Artist   string     `json:"artist" example:"Brian Eno"`
+	Role     CreditRole `json:"role" example:"producer"`
+}
+
+// Song credits, stored as JSON array
+type Credits []Credit
+
+func (c *Credits) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*c = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	}
+
+	return fmt.Errorf("unsupported credits source type %T", src)
+}
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// Queued lookup of song details
+type EnrichmentJob struct {
+	ID        uint64    `db:"id"`
+	SongID    uint64    `db:"song_id"`
+	Attempts  uint      `db:"attempts"`
+	RunAt     time.Time `db:"run_at"`
+	LastError string    `db:"last_error"`
+
+	// Delay before next attempt
+	RetryIn time.Duration `db:"-"`
+
+	// Song being enriched
+	Song Song `db:"-"`
+}
+
+// Enrichment progress of song
+type EnrichmentState struct {
+	SongID    uint64           `db:"id" json:"id"`
+	Status    EnrichmentStatus `db:"enrichment_status" json:"enrichment_status" example:"pending"`
+	Attempts  *uint            `db:"attempts" json:"attempts,omitempty"`
+	RunAt     *time.Time       `db:"run_at" json:"next_attempt_at,omitempty"`
+	LastError *string          `db:"last_error" json:"last_error,omitempty"`
+}
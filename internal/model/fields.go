@@ -0,0 +1,107 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"slices"
+)
+
+// Names of song fields as they appear in requests
+const (
+	FieldName        = "song"
+	FieldGroup       = "group"
+	FieldText        = "text"
+	FieldReleaseDate = "release_date"
+	FieldLink        = "link"
+)
+
+// Song credits and classification, they are never looked up by provider
+const (
+	FieldCredits = "credits"
+	FieldGenres  = "genres"
+	FieldTags    = "tags"
+)
+
+// Song fields computed from lyrics, only selected on request
+const (
+	FieldTextLength = "text_length"
+	FieldVerseCount = "verse_count"
+)
+
+// Song fields kept in DB
+var StoredFields = FieldSet{
+	"id",
+	"created_at",
+	"updated_at",
+	"version",
+	FieldName,
+	FieldGroup,
+	"artist_id",
+	FieldCredits,
+	FieldGenres,
+	FieldTags,
+	FieldText,
+	FieldReleaseDate,
+	FieldLink,
+	"enrichment_status",
+	"manual_fields",
+	"deleted_at",
+}
+
+// Fields of song list when request doesn't select any, lyrics are left out
+var DefaultListFields = StoredFields.Without(FieldText).With(FieldTextLength, FieldVerseCount)
+
+// Set of song field names, stored as JSON array
+type FieldSet []string
+
+func (f FieldSet) Has(field string) bool {
+	return slices.Contains(f, field)
+}
+
+// Returns copy of set with fields added
+func (f FieldSet) With(fields ...string) FieldSet {
+	result := slices.Clone(f)
+
+	for _, field := range fields {
+		if !result.Has(field) {
+			result = append(result, field)
+		}
+	}
+
+	return result
+}
+
+// Returns copy of set with fields removed
+func (f FieldSet) Without(fields ...string) FieldSet {
+	return slices.DeleteFunc(slices.Clone(f), func(field string) bool {
+		return slices.Contains(fields, field)
+	})
+}
+
+func (f *FieldSet) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*f = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	}
+
+	return fmt.Errorf("unsupported field set source type %T", src)
+}
+
+func (f FieldSet) Value() (driver.Value, error) {
+	if f == nil {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
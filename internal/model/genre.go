@@ -0,0 +1,34 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type Genre struct {
+	ID        uint64    `db:"id" json:"id" example:"1"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	Name      string    `db:"name" json:"name" example:"Rock"`
+	Slug      string    `db:"slug" json:"slug" example:"rock"`
+	SongCount uint64    `db:"song_count" json:"song_count" example:"124"`
+}
+
+type Genres []*Genre
+
+// Genre slugs or tags of song, stored as JSON array
+type Labels []string
+
+func (l *Labels) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	}
+
+	return fmt.Errorf("unsupported labels source type %T", src)
+}
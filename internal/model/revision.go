@@ -0,0 +1,74 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Saved state of song fields
+type SongRevision struct {
+	Revision    uint64    `db:"revision" json:"revision" example:"3"`
+	SongID      uint64    `db:"song_id" json:"song_id" example:"1"`
+	SongVersion uint64    `db:"song_version" json:"song_version" example:"7"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	Author      string    `db:"author" json:"author" example:"editor@example.com"`
+	Reason      string    `db:"reason" json:"reason" example:"fixed typo in chorus"`
+
+	// Song fields, left out of revision lists
+	Snapshot *SongSnapshot `db:"snapshot" json:"snapshot,omitempty"`
+}
+
+type SongRevisions []*SongRevision
+
+// Editable song fields, stored as JSON object
+type SongSnapshot struct {
+	Name        string  `json:"song" example:"Supermassive Black Hole"`
+	Group       string  `json:"group" example:"Muse"`
+	Credits     Credits `json:"credits"`
+	Genres      Labels  `json:"genres" example:"rock,alternative"`
+	Tags        Labels  `json:"tags" example:"space,falsetto"`
+	Text        string  `json:"text"`
+	ReleaseDate *string `json:"release_date" example:"2006-07-16"`
+	Link        string  `json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+}
+
+func NewSongSnapshot(song *Song) *SongSnapshot {
+	snapshot := &SongSnapshot{
+		Name:    song.Name,
+		Group:   song.Group,
+		Credits: song.Credits,
+		Genres:  song.Genres,
+		Tags:    song.Tags,
+		Text:    song.Text,
+		Link:    song.Link,
+	}
+
+	if song.ReleaseDate != nil {
+		date := song.ReleaseDate.Format(time.DateOnly)
+		snapshot.ReleaseDate = &date
+	}
+
+	return snapshot
+}
+
+func (s *SongSnapshot) Scan(src any) error {
+	switch v := src.(type) {
+	case []byte:
+		return json.Unmarshal(v, s)
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	}
+
+	return fmt.Errorf("unsupported snapshot source type %T", src)
+}
+
+func (s SongSnapshot) Value() (driver.Value, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Kind of lyrics section
+type SectionType string
+
+const (
+	SectionVerse  SectionType = "verse"
+	SectionChorus SectionType = "chorus"
+	SectionBridge SectionType = "bridge"
+	SectionIntro  SectionType = "intro"
+	SectionOutro  SectionType = "outro"
+)
+
+var SectionTypes = []SectionType{SectionVerse, SectionChorus, SectionBridge, SectionIntro, SectionOutro}
+
+// Part of song lyrics separated by blank lines
+type Section struct {
+	Index int         `db:"section_index" json:"index" example:"1"`
+	Type  SectionType `db:"section_type" json:"type" example:"chorus"`
+	Label string      `db:"label" json:"label" example:"Chorus"`
+	Lines Lines       `db:"lines" json:"lines" example:"Glaciers melting in the dead of night"`
+
+	// Index of first section with same lines
+	RepeatOf *int `db:"repeat_of" json:"repeat_of,omitempty" example:"1"`
+}
+
+type Sections []*Section
+
+// Lines of lyrics section, stored as JSON array
+type Lines []string
+
+func (l *Lines) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*l = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	}
+
+	return fmt.Errorf("unsupported lines source type %T", src)
+}
@@ -1,16 +1,112 @@
 package model
 
-import "time"
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// State of song details lookup
+type EnrichmentStatus string
+
+const (
+	EnrichmentPending  EnrichmentStatus = "pending"
+	EnrichmentEnriched EnrichmentStatus = "enriched"
+	EnrichmentFailed   EnrichmentStatus = "failed"
+)
 
 type Song struct {
-	ID          uint64    `db:"id"`
-	CreatedAt   time.Time `db:"created_at"`
-	UpdatedAt   time.Time `db:"updated_at"`
-	Name        string    `db:"song_name"`
-	Group       string    `db:"song_group"`
-	Text        string    `db:"song_text"`
-	ReleaseDate time.Time `db:"release_date"`
-	Link        string    `db:"link"`
+	ID               uint64           `db:"id" json:"id" example:"1"`
+	CreatedAt        time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt        time.Time        `db:"updated_at" json:"updated_at"`
+	Version          uint64           `db:"version" json:"version" example:"1"`
+	Name             string           `db:"song_name" json:"song" example:"Supermassive Black Hole"`
+	Group            string           `db:"song_group" json:"group" example:"Muse"`
+	ArtistID         uint64           `db:"artist_id" json:"artist_id" example:"1"`
+	Credits          Credits          `db:"credits" json:"credits"`
+	Genres           Labels           `db:"genres" json:"genres" example:"rock,alternative"`
+	Tags             Labels           `db:"tags" json:"tags" example:"space,falsetto"`
+	Text             string           `db:"song_text" json:"text"`
+	ReleaseDate      *time.Time       `db:"release_date" json:"release_date" example:"2006-07-16T00:00:00Z"`
+	Link             string           `db:"link" json:"link" example:"https://www.youtube.com/watch?v=Xsp3_a-PMTw"`
+	EnrichmentStatus EnrichmentStatus `db:"enrichment_status" json:"enrichment_status" example:"enriched"`
+
+	// Fields set by hand, they are kept on refresh unless forced
+	ManualFields FieldSet `db:"manual_fields" json:"manual_fields"`
+
+	// Time song was moved to trash
+	DeletedAt *time.Time `db:"deleted_at" json:"deleted_at,omitempty"`
+
+	// Search hit relevance and lyrics snippet
+	Rank     *float64 `db:"rank" json:"rank,omitempty"`
+	Headline *string  `db:"headline" json:"headline,omitempty"`
+
+	// Name and group similarity in fuzzy match mode
+	Score *float64 `db:"score" json:"score,omitempty"`
+
+	// Lyrics size, selected instead of lyrics
+	TextLength *int `db:"text_length" json:"text_length,omitempty" example:"1830"`
+	VerseCount *int `db:"verse_count" json:"verse_count,omitempty" example:"6"`
 }
 
 type Songs []*Song
+
+// Members of song JSON kept in any field selection
+var alwaysFields = FieldSet{"id", "rank", "headline", "score"}
+
+// Song encoded with selected fields only, all fields are encoded when none are selected
+type PartialSong struct {
+	Song   *Song
+	Fields FieldSet
+}
+
+func (p PartialSong) MarshalJSON() ([]byte, error) {
+	full, err := json.Marshal(p.Song)
+	if err != nil || len(p.Fields) == 0 {
+		return full, err
+	}
+
+	var result bytes.Buffer
+
+	// Members are copied in original order
+	decoder := json.NewDecoder(bytes.NewReader(full))
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, err
+	}
+
+	result.WriteByte('{')
+
+	for decoder.More() {
+		var value json.RawMessage
+
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		err = decoder.Decode(&value)
+		if err != nil {
+			return nil, err
+		}
+
+		field, _ := token.(string)
+
+		if !p.Fields.Has(field) && !alwaysFields.Has(field) {
+			continue
+		}
+
+		if result.Len() > 1 {
+			result.WriteByte(',')
+		}
+
+		key, _ := json.Marshal(field)
+		result.Write(key)
+		result.WriteByte(':')
+		result.Write(value)
+	}
+
+	result.WriteByte('}')
+
+	return result.Bytes(), nil
+}
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Line of synchronized lyrics, offsets are in milliseconds from song start
+type TimedLine struct {
+	Index int    `db:"line_index" json:"index" example:"3"`
+	Start int64  `db:"start_ms" json:"start_ms" example:"12500"`
+	Text  string `db:"text" json:"text" example:"You set my soul alight"`
+
+	// End of last word, set by enhanced LRC only
+	End   *int64     `db:"end_ms" json:"end_ms,omitempty" example:"15200"`
+	Words TimedWords `db:"words" json:"words,omitempty"`
+}
+
+type TimedLines []*TimedLine
+
+// Word of synchronized lyrics line
+type TimedWord struct {
+	Start int64  `json:"start_ms" example:"13100"`
+	Text  string `json:"text" example:"soul"`
+}
+
+// Word timings of line, stored as JSON array
+type TimedWords []TimedWord
+
+func (w *TimedWords) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*w = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, w)
+	case string:
+		return json.Unmarshal([]byte(v), w)
+	}
+
+	return fmt.Errorf("unsupported words source type %T", src)
+}
+
+func (w TimedWords) Value() (driver.Value, error) {
+	if w == nil {
+		return "[]", nil
+	}
+
+	b, err := json.Marshal(w)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
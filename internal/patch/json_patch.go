@@ -0,0 +1,316 @@
+package patch
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// Applies sequence of JSON Patch operations to document, either all or none of them are applied
+func JSONPatch(doc, patch []byte) ([]byte, error) {
+	var ops []operation
+
+	err := json.Unmarshal(patch, &ops)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidPatch, err.Error())
+	}
+
+	root, err := decode(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode document")
+	}
+
+	for i, op := range ops {
+		root, err = op.apply(root)
+		if err != nil {
+			return nil, errors.Wrapf(err, "operation %d (%s)", i, op.Op)
+		}
+	}
+
+	return json.Marshal(root)
+}
+
+func (op operation) apply(root any) (any, error) {
+	if op.Path == nil {
+		return nil, errors.Wrap(ErrInvalidPatch, "missing path")
+	}
+
+	path, err := parsePointer(*op.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch op.Op {
+	case "add", "replace", "test":
+		value, err := op.value()
+		if err != nil {
+			return nil, err
+		}
+
+		switch op.Op {
+		case "add":
+			return add(root, path, value)
+		case "replace":
+			return replace(root, path, value)
+		}
+
+		current, err := get(root, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !reflect.DeepEqual(current, value) {
+			return nil, errors.Wrapf(ErrTestFailed, "value at %q differs", *op.Path)
+		}
+
+		return root, nil
+	case "remove":
+		return remove(root, path)
+	case "move", "copy":
+		if op.From == nil {
+			return nil, errors.Wrap(ErrInvalidPatch, "missing from")
+		}
+
+		from, err := parsePointer(*op.From)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := get(root, from)
+		if err != nil {
+			return nil, err
+		}
+
+		if op.Op == "copy" {
+			value, err = deepCopy(value)
+			if err != nil {
+				return nil, err
+			}
+
+			return add(root, path, value)
+		}
+
+		// Location can't be moved into its own child
+		if len(path) > len(from) && reflect.DeepEqual(path[:len(from)], from) {
+			return nil, errors.Wrap(ErrInvalidPath, "can't move value into itself")
+		}
+
+		root, err = remove(root, from)
+		if err != nil {
+			return nil, err
+		}
+
+		return add(root, path, value)
+	}
+
+	return nil, errors.Wrapf(ErrInvalidPatch, "unknown operation %q", op.Op)
+}
+
+// Decodes operation value, it must be present even if null
+func (op operation) value() (any, error) {
+	if op.Value == nil {
+		return nil, errors.Wrap(ErrInvalidPatch, "missing value")
+	}
+
+	return decode(op.Value)
+}
+
+// Splits JSON pointer (RFC 6901) into reference tokens
+func parsePointer(pointer string) ([]string, error) {
+	if len(pointer) == 0 {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Wrapf(ErrInvalidPatch, "invalid pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+
+	return tokens, nil
+}
+
+func get(node any, path []string) (any, error) {
+	for _, token := range path {
+		var err error
+
+		node, err = child(node, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return node, nil
+}
+
+func add(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(root, path, func(container any, key string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			c[key] = value
+			return c, nil
+		case []any:
+			if key == "-" {
+				return append(c, value), nil
+			}
+
+			i, err := index(key, len(c)+1)
+			if err != nil {
+				return nil, err
+			}
+
+			c = append(c, nil)
+			copy(c[i+1:], c[i:])
+			c[i] = value
+
+			return c, nil
+		}
+
+		return nil, errors.Wrapf(ErrInvalidPath, "%q isn't in container", key)
+	})
+}
+
+func remove(root any, path []string) (any, error) {
+	if len(path) == 0 {
+		return nil, errors.Wrap(ErrInvalidPath, "can't remove whole document")
+	}
+
+	return update(root, path, func(container any, key string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			if _, ok := c[key]; !ok {
+				return nil, errors.Wrapf(ErrInvalidPath, "member %q doesn't exist", key)
+			}
+
+			delete(c, key)
+			return c, nil
+		case []any:
+			i, err := index(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+
+			return append(c[:i], c[i+1:]...), nil
+		}
+
+		return nil, errors.Wrapf(ErrInvalidPath, "%q isn't in container", key)
+	})
+}
+
+func replace(root any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	return update(root, path, func(container any, key string) (any, error) {
+		switch c := container.(type) {
+		case map[string]any:
+			if _, ok := c[key]; !ok {
+				return nil, errors.Wrapf(ErrInvalidPath, "member %q doesn't exist", key)
+			}
+
+			c[key] = value
+			return c, nil
+		case []any:
+			i, err := index(key, len(c))
+			if err != nil {
+				return nil, err
+			}
+
+			c[i] = value
+			return c, nil
+		}
+
+		return nil, errors.Wrapf(ErrInvalidPath, "%q isn't in container", key)
+	})
+}
+
+// Applies fn to container holding last path token, containers along the path are updated with its result
+func update(node any, path []string, fn func(container any, key string) (any, error)) (any, error) {
+	if len(path) == 1 {
+		return fn(node, path[0])
+	}
+
+	next, err := child(node, path[0])
+	if err != nil {
+		return nil, err
+	}
+
+	next, err = update(next, path[1:], fn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch c := node.(type) {
+	case map[string]any:
+		c[path[0]] = next
+	case []any:
+		i, _ := index(path[0], len(c))
+		c[i] = next
+	}
+
+	return node, nil
+}
+
+func child(node any, token string) (any, error) {
+	switch c := node.(type) {
+	case map[string]any:
+		value, ok := c[token]
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidPath, "member %q doesn't exist", token)
+		}
+
+		return value, nil
+	case []any:
+		i, err := index(token, len(c))
+		if err != nil {
+			return nil, err
+		}
+
+		return c[i], nil
+	}
+
+	return nil, errors.Wrapf(ErrInvalidPath, "%q isn't in container", token)
+}
+
+// Parses array index, it must be less than limit
+func index(token string, limit int) (int, error) {
+	// Leading zeros aren't allowed
+	if len(token) > 1 && token[0] == '0' {
+		return 0, errors.Wrapf(ErrInvalidPath, "invalid array index %q", token)
+	}
+
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i >= limit {
+		return 0, errors.Wrapf(ErrInvalidPath, "array index %q is out of range", token)
+	}
+
+	return i, nil
+}
+
+func deepCopy(value any) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return decode(data)
+}
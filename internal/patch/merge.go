@@ -0,0 +1,45 @@
+package patch
+
+import (
+	"encoding/json"
+
+	"github.com/pkg/errors"
+)
+
+// Applies merge patch to document, null values in patch remove members
+func MergePatch(doc, patch []byte) ([]byte, error) {
+	target, err := decode(doc)
+	if err != nil {
+		return nil, errors.Wrap(err, "decode document")
+	}
+
+	p, err := decode(patch)
+	if err != nil || p == nil {
+		return nil, ErrInvalidPatch
+	}
+
+	return json.Marshal(mergeValue(target, p))
+}
+
+func mergeValue(target, patch any) any {
+	patchObj, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObj, ok := target.(map[string]any)
+	if !ok {
+		targetObj = make(map[string]any)
+	}
+
+	for key, value := range patchObj {
+		if value == nil {
+			delete(targetObj, key)
+			continue
+		}
+
+		targetObj[key] = mergeValue(targetObj[key], value)
+	}
+
+	return targetObj
+}
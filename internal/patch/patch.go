@@ -0,0 +1,26 @@
+// Applies JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902) documents
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+var (
+	ErrInvalidPatch = errors.New("invalid patch document")
+	ErrInvalidPath  = errors.New("patch path can't be applied to document")
+	ErrTestFailed   = errors.New("patch test operation failed")
+)
+
+// Decodes JSON document into generic value tree
+func decode(data []byte) (any, error) {
+	var v any
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	err := json.Unmarshal(data, &v)
+
+	return v, err
+}
@@ -0,0 +1,281 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const testDoc = `{"name":"Song","tags":["rock","pop"],"a/b":1,"m~n":2,"detail":{"link":"x"}}`
+
+func TestJSONPatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+		want  string
+		err   error
+	}{
+		{
+			name:  "add member",
+			patch: `[{"op":"add","path":"/link","value":"https://example.com"}]`,
+			want:  `{"name":"Song","tags":["rock","pop"],"a/b":1,"m~n":2,"detail":{"link":"x"},"link":"https://example.com"}`,
+		},
+		{
+			name:  "add null value",
+			patch: `[{"op":"add","path":"/link","value":null}]`,
+			want:  `{"name":"Song","tags":["rock","pop"],"a/b":1,"m~n":2,"detail":{"link":"x"},"link":null}`,
+		},
+		{
+			name:  "add without value",
+			patch: `[{"op":"add","path":"/link"}]`,
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "add array element by index",
+			patch: `[{"op":"add","path":"/tags/1","value":"jazz"}]`,
+			want:  `{"name":"Song","tags":["rock","jazz","pop"],"a/b":1,"m~n":2,"detail":{"link":"x"}}`,
+		},
+		{
+			name:  "add array element after last",
+			patch: `[{"op":"add","path":"/tags/2","value":"jazz"}]`,
+			want:  `{"name":"Song","tags":["rock","pop","jazz"],"a/b":1,"m~n":2,"detail":{"link":"x"}}`,
+		},
+		{
+			name:  "append array element",
+			patch: `[{"op":"add","path":"/tags/-","value":"jazz"}]`,
+			want:  `{"name":"Song","tags":["rock","pop","jazz"],"a/b":1,"m~n":2,"detail":{"link":"x"}}`,
+		},
+		{
+			name:  "add array element out of range",
+			patch: `[{"op":"add","path":"/tags/3","value":"jazz"}]`,
+			err:   ErrInvalidPath,
+		},
+		{
+			name:  "array index with leading zero",
+			patch: `[{"op":"replace","path":"/tags/01","value":"jazz"}]`,
+			err:   ErrInvalidPath,
+		},
+		{
+			name:  "negative array index",
+			patch: `[{"op":"remove","path":"/tags/-1"}]`,
+			err:   ErrInvalidPath,
+		},
+		{
+			name:  "remove array element",
+			patch: `[{"op":"remove","path":"/tags/0"}]`,
+			want:  `{"name":"Song","tags":["pop"],"a/b":1,"m~n":2,"detail":{"link":"x"}}`,
+		},
+		{
+			name:  "remove missing member",
+			patch: `[{"op":"remove","path":"/link"}]`,
+			err:   ErrInvalidPath,
+		},
+		{
+			name:  "remove whole document",
+			patch: `[{"op":"remove","path":""}]`,
+			err:   ErrInvalidPath,
+		},
+		{
+			name:  "replace nested member",
+			patch: `[{"op":"replace","path":"/detail/link","value":"y"}]`,
+			want:  `{"name":"Song","tags":["rock","pop"],"a/b":1,"m~n":2,"detail":{"link":"y"}}`,
+		},
+		{
+			name:  "replace missing member",
+			patch: `[{"op":"replace","path":"/link","value":"y"}]`,
+			err:   ErrInvalidPath,
+		},
+		{
+			name:  "escaped slash in path",
+			patch: `[{"op":"replace","path":"/a~1b","value":3}]`,
+			want:  `{"name":"Song","tags":["rock","pop"],"a/b":3,"m~n":2,"detail":{"link":"x"}}`,
+		},
+		{
+			name:  "escaped tilde in path",
+			patch: `[{"op":"remove","path":"/m~0n"}]`,
+			want:  `{"name":"Song","tags":["rock","pop"],"a/b":1,"detail":{"link":"x"}}`,
+		},
+		{
+			name:  "path without leading slash",
+			patch: `[{"op":"remove","path":"name"}]`,
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "move member",
+			patch: `[{"op":"move","from":"/detail/link","path":"/link"}]`,
+			want:  `{"name":"Song","tags":["rock","pop"],"a/b":1,"m~n":2,"detail":{},"link":"x"}`,
+		},
+		{
+			name:  "move array element",
+			patch: `[{"op":"move","from":"/tags/0","path":"/tags/-"}]`,
+			want:  `{"name":"Song","tags":["pop","rock"],"a/b":1,"m~n":2,"detail":{"link":"x"}}`,
+		},
+		{
+			name:  "move into own child",
+			patch: `[{"op":"move","from":"/detail","path":"/detail/inner"}]`,
+			err:   ErrInvalidPath,
+		},
+		{
+			name:  "copy member",
+			patch: `[{"op":"copy","from":"/detail","path":"/copy"},{"op":"replace","path":"/copy/link","value":"y"}]`,
+			want:  `{"name":"Song","tags":["rock","pop"],"a/b":1,"m~n":2,"detail":{"link":"x"},"copy":{"link":"y"}}`,
+		},
+		{
+			name:  "copy without from",
+			patch: `[{"op":"copy","path":"/copy"}]`,
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "test passes",
+			patch: `[{"op":"test","path":"/tags","value":["rock","pop"]},{"op":"replace","path":"/name","value":"New"}]`,
+			want:  `{"name":"New","tags":["rock","pop"],"a/b":1,"m~n":2,"detail":{"link":"x"}}`,
+		},
+		{
+			name:  "test number",
+			patch: `[{"op":"test","path":"/a~1b","value":1}]`,
+			want:  testDoc,
+		},
+		{
+			name:  "test fails",
+			patch: `[{"op":"replace","path":"/name","value":"New"},{"op":"test","path":"/tags/0","value":"pop"}]`,
+			err:   ErrTestFailed,
+		},
+		{
+			name:  "test missing member",
+			patch: `[{"op":"test","path":"/link","value":null}]`,
+			err:   ErrInvalidPath,
+		},
+		{
+			name:  "unknown operation",
+			patch: `[{"op":"swap","path":"/name"}]`,
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "missing path",
+			patch: `[{"op":"remove"}]`,
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "patch isn't array",
+			patch: `{"op":"remove","path":"/name"}`,
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "empty patch",
+			patch: `[]`,
+			want:  testDoc,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONPatch([]byte(testDoc), []byte(tt.patch))
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		doc   string
+		patch string
+		want  string
+		err   error
+	}{
+		{
+			name:  "replace member",
+			doc:   `{"name":"Song","link":"x"}`,
+			patch: `{"name":"New"}`,
+			want:  `{"name":"New","link":"x"}`,
+		},
+		{
+			name:  "null removes member",
+			doc:   `{"name":"Song","link":"x"}`,
+			patch: `{"link":null}`,
+			want:  `{"name":"Song"}`,
+		},
+		{
+			name:  "nested object is merged",
+			doc:   `{"detail":{"link":"x","text":"y"}}`,
+			patch: `{"detail":{"text":"z"}}`,
+			want:  `{"detail":{"link":"x","text":"z"}}`,
+		},
+		{
+			name:  "array is replaced",
+			doc:   `{"tags":["rock","pop"]}`,
+			patch: `{"tags":["jazz"]}`,
+			want:  `{"tags":["jazz"]}`,
+		},
+		{
+			name:  "object replaces scalar",
+			doc:   `{"detail":"x"}`,
+			patch: `{"detail":{"link":"y"}}`,
+			want:  `{"detail":{"link":"y"}}`,
+		},
+		{
+			name:  "null patch",
+			doc:   `{"name":"Song"}`,
+			patch: `null`,
+			err:   ErrInvalidPatch,
+		},
+		{
+			name:  "malformed patch",
+			doc:   `{"name":"Song"}`,
+			patch: `{"name":`,
+			err:   ErrInvalidPatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MergePatch([]byte(tt.doc), []byte(tt.patch))
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			assertJSONEqual(t, got, tt.want)
+		})
+	}
+}
+
+func assertJSONEqual(t *testing.T, got []byte, want string) {
+	t.Helper()
+
+	var g, w any
+
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("invalid result %s: %v", got, err)
+	}
+
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("invalid expectation %s: %v", want, err)
+	}
+
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,384 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Earliest release date of albums song is listed on
+const albumReleaseDate = `(SELECT min(a.release_date) FROM album_tracks t
+	JOIN albums a ON a.id = t.album_id WHERE t.song_id = songs.id)`
+
+// Album storage repository
+type AlbumRepository interface {
+	List(ctx context.Context, artistID *uint64, cursor string, limit uint) (*domain.AlbumPage, error)
+	GetById(ctx context.Context, albumID uint64) (*model.Album, error)
+	Create(ctx context.Context, album *model.Album) error
+	Update(ctx context.Context, album *model.Album) error
+	Delete(ctx context.Context, albumID uint64) error
+	SetTracks(ctx context.Context, albumID uint64, tracks []model.Track) error
+	SongReleaseDate(ctx context.Context, songID uint64) (*time.Time, error)
+}
+
+type PgAlbumRepository struct {
+	db *sqlx.DB
+}
+
+func NewPgAlbumRepository(db *sqlx.DB) *PgAlbumRepository {
+	return &PgAlbumRepository{
+		db: db,
+	}
+}
+
+// Returns query selecting albums with artist name and number of tracks
+func albumSelect() sq.SelectBuilder {
+	return sq.Select(
+		"al.id",
+		"al.created_at",
+		"al.updated_at",
+		"al.title",
+		"al.artist_id",
+		"ar.name AS artist_name",
+		"al.release_date",
+		"al.album_type",
+		"al.cover_link",
+		`(SELECT count(*) FROM album_tracks t JOIN songs s ON s.id = t.song_id
+			WHERE t.album_id = al.id AND s.deleted_at IS NULL) AS track_count`,
+	).
+		From("albums al").
+		Join("artists ar ON ar.id = al.artist_id").
+		PlaceholderFormat(sq.Dollar)
+}
+
+// Fetches page of albums added before album in cursor, optionally of single artist
+func (r *PgAlbumRepository) List(ctx context.Context, artistID *uint64, cursor string, limit uint) (*domain.AlbumPage, error) {
+	albums := make(model.Albums, 0)
+
+	// Extra album tells whether there are more albums
+	sb := albumSelect().
+		OrderBy("al.id DESC").
+		Limit(uint64(limit) + 1)
+
+	if artistID != nil {
+		sb = sb.Where(sq.Eq{
+			"al.artist_id": *artistID,
+		})
+	}
+
+	if len(cursor) > 0 {
+		lastID, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(domain.ErrInvalidCursor, err.Error())
+		}
+
+		sb = sb.Where(sq.Lt{
+			"al.id": lastID,
+		})
+	}
+
+	query, args, err := sb.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.List")
+	}
+
+	err = r.db.SelectContext(ctx, &albums, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.List")
+	}
+
+	page := &domain.AlbumPage{
+		Limit: limit,
+	}
+
+	if uint(len(albums)) > limit {
+		albums = albums[:limit]
+		next := strconv.FormatUint(albums[len(albums)-1].ID, 10)
+		page.NextCursor = &next
+	}
+
+	page.Albums = albums
+
+	return page, nil
+}
+
+// Fetches album with its track listing
+func (r *PgAlbumRepository) GetById(ctx context.Context, albumID uint64) (*model.Album, error) {
+	var album model.Album
+
+	query, args, err := albumSelect().
+		Where(sq.Eq{
+			"al.id": albumID,
+		}).
+		ToSql()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.GetById")
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&album)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrAlbumNotFound
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.GetById")
+	}
+
+	album.Tracks = make([]model.Track, 0)
+
+	query, args, err = sq.Select(
+		"t.song_id",
+		"s.song_name",
+		"s.song_group",
+		"t.disc_number",
+		"t.track_number",
+	).
+		From("album_tracks t").
+		Join("songs s ON s.id = t.song_id").
+		Where(sq.Eq{
+			"t.album_id":   albumID,
+			"s.deleted_at": nil,
+		}).
+		OrderBy("t.disc_number", "t.track_number").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.GetById")
+	}
+
+	err = r.db.SelectContext(ctx, &album.Tracks, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.GetById")
+	}
+
+	return &album, nil
+}
+
+func (r *PgAlbumRepository) Create(ctx context.Context, album *model.Album) error {
+	err := checkArtistExists(ctx, r.db, album.ArtistID)
+	if err != nil {
+		return err
+	}
+
+	query, args, err := sq.StatementBuilder.
+		Insert("albums").
+		Columns("title", "artist_id", "release_date", "album_type", "cover_link").
+		Values(album.Title, album.ArtistID, album.ReleaseDate, album.Type, album.CoverLink).
+		Suffix("RETURNING id, created_at, updated_at").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if err != nil {
+		return errors.Wrap(err, "repository.Create")
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).Scan(&album.ID, &album.CreatedAt, &album.UpdatedAt)
+
+	return errors.Wrap(err, "repository.Create")
+}
+
+// Replaces album fields
+func (r *PgAlbumRepository) Update(ctx context.Context, album *model.Album) error {
+	err := checkArtistExists(ctx, r.db, album.ArtistID)
+	if err != nil {
+		return err
+	}
+
+	res, err := sq.StatementBuilder.
+		Update("albums").
+		Set("updated_at", time.Now()).
+		Set("title", album.Title).
+		Set("artist_id", album.ArtistID).
+		Set("release_date", album.ReleaseDate).
+		Set("album_type", album.Type).
+		Set("cover_link", album.CoverLink).
+		Where(sq.Eq{
+			"id": album.ID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db).
+		ExecContext(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.Update")
+	}
+
+	return albumAffected(res, "repository.Update")
+}
+
+// Removes album, its songs are kept
+func (r *PgAlbumRepository) Delete(ctx context.Context, albumID uint64) error {
+	res, err := sq.StatementBuilder.
+		Delete("albums").
+		Where(sq.Eq{
+			"id": albumID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(r.db).
+		ExecContext(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.Delete")
+	}
+
+	return albumAffected(res, "repository.Delete")
+}
+
+// Replaces track listing of album
+func (r *PgAlbumRepository) SetTracks(ctx context.Context, albumID uint64, tracks []model.Track) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "repository.SetTracks")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Lock album against concurrent listing changes
+	var locked uint64
+
+	err = tx.QueryRowxContext(ctx, "SELECT id FROM albums WHERE id = $1 FOR UPDATE", albumID).Scan(&locked)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrAlbumNotFound
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "repository.SetTracks")
+	}
+
+	_, err = sq.StatementBuilder.
+		Delete("album_tracks").
+		Where(sq.Eq{
+			"album_id": albumID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.SetTracks")
+	}
+
+	if len(tracks) > 0 {
+		err = checkSongsExist(ctx, tx, tracks)
+		if err != nil {
+			return err
+		}
+
+		ib := sq.StatementBuilder.
+			Insert("album_tracks").
+			Columns("album_id", "song_id", "disc_number", "track_number").
+			PlaceholderFormat(sq.Dollar)
+
+		for _, track := range tracks {
+			ib = ib.Values(albumID, track.SongID, track.DiscNumber, track.TrackNumber)
+		}
+
+		_, err = ib.RunWith(tx).ExecContext(ctx)
+		if err != nil {
+			return errors.Wrap(err, "repository.SetTracks")
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE albums SET updated_at = $1 WHERE id = $2", time.Now(), albumID)
+	if err != nil {
+		return errors.Wrap(err, "repository.SetTracks")
+	}
+
+	return errors.Wrap(tx.Commit(), "repository.SetTracks")
+}
+
+// Returns earliest release date of albums song is listed on, nil if there is none
+func (r *PgAlbumRepository) SongReleaseDate(ctx context.Context, songID uint64) (*time.Time, error) {
+	var date *time.Time
+
+	err := r.db.QueryRowxContext(ctx, "SELECT "+albumReleaseDate+" FROM songs WHERE id = $1 AND deleted_at IS NULL", songID).Scan(&date)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSongNotFound
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.SongReleaseDate")
+	}
+
+	return date, nil
+}
+
+func checkArtistExists(ctx context.Context, q sqlx.QueryerContext, artistID uint64) error {
+	var exists bool
+
+	err := q.QueryRowxContext(ctx, "SELECT EXISTS (SELECT 1 FROM artists WHERE id = $1)", artistID).Scan(&exists)
+	if err != nil {
+		return errors.Wrap(err, "repository.checkArtistExists")
+	}
+
+	if !exists {
+		return errors.Wrapf(domain.ErrArtistNotFound, "id %d", artistID)
+	}
+
+	return nil
+}
+
+func checkSongsExist(ctx context.Context, tx *sqlx.Tx, tracks []model.Track) error {
+	var found []uint64
+
+	ids := make([]uint64, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.SongID
+	}
+
+	query, args, err := sq.Select("id").
+		From("songs").
+		Where(sq.Eq{
+			"id":         ids,
+			"deleted_at": nil,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if err != nil {
+		return errors.Wrap(err, "repository.checkSongsExist")
+	}
+
+	err = tx.SelectContext(ctx, &found, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "repository.checkSongsExist")
+	}
+
+	if len(found) == len(ids) {
+		return nil
+	}
+
+	for _, id := range ids {
+		if !slices.Contains(found, id) {
+			return errors.Wrapf(domain.ErrSongNotFound, "id %d", id)
+		}
+	}
+
+	return nil
+}
+
+// Reports missing album when statement changed nothing
+func albumAffected(res sql.Result, op string) error {
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, op)
+	}
+
+	if affected == 0 {
+		return domain.ErrAlbumNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,566 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Advisory lock serializing changes of artist names and aliases.
+// It is always taken before song rows are locked, so renames and merges can't deadlock with song updates
+const artistLockKey = 7_301_001
+
+// Artist storage repository
+type ArtistRepository interface {
+	List(ctx context.Context, cursor string, limit uint) (*domain.ArtistPage, error)
+	GetById(ctx context.Context, artistID uint64) (*model.Artist, error)
+	GetBySlug(ctx context.Context, slug string) (*model.Artist, error)
+	Rename(ctx context.Context, artistID uint64, name string) (*model.Artist, error)
+	MergeAliases(ctx context.Context, artistID uint64, aliases []string) (*model.Artist, error)
+	Delete(ctx context.Context, artistID uint64) error
+}
+
+type PgArtistRepository struct {
+	db *sqlx.DB
+}
+
+func NewPgArtistRepository(db *sqlx.DB) *PgArtistRepository {
+	return &PgArtistRepository{
+		db: db,
+	}
+}
+
+// Returns query selecting artists with their aliases and number of songs
+func artistSelect() sq.SelectBuilder {
+	return sq.Select(
+		"id",
+		"created_at",
+		"updated_at",
+		"name",
+		"slug",
+		`(SELECT coalesce(jsonb_agg(alias ORDER BY alias), '[]') FROM artist_aliases
+			WHERE artist_aliases.artist_id = artists.id) AS aliases`,
+		"(SELECT count(*) FROM songs WHERE songs.artist_id = artists.id AND songs.deleted_at IS NULL) AS song_count",
+	).
+		From("artists").
+		PlaceholderFormat(sq.Dollar)
+}
+
+// Fetches page of artists following slug in cursor
+func (r *PgArtistRepository) List(ctx context.Context, cursor string, limit uint) (*domain.ArtistPage, error) {
+	artists := make(model.Artists, 0)
+
+	// Extra artist tells whether there are more artists
+	sb := artistSelect().
+		OrderBy("slug").
+		Limit(uint64(limit) + 1)
+
+	if len(cursor) > 0 {
+		sb = sb.Where(sq.Gt{
+			"slug": cursor,
+		})
+	}
+
+	query, args, err := sb.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.List")
+	}
+
+	err = r.db.SelectContext(ctx, &artists, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.List")
+	}
+
+	page := &domain.ArtistPage{
+		Limit: limit,
+	}
+
+	if uint(len(artists)) > limit {
+		artists = artists[:limit]
+		page.NextCursor = &artists[len(artists)-1].Slug
+	}
+
+	page.Artists = artists
+
+	return page, nil
+}
+
+func (r *PgArtistRepository) GetById(ctx context.Context, artistID uint64) (*model.Artist, error) {
+	return getArtist(ctx, r.db, sq.Eq{"id": artistID})
+}
+
+func (r *PgArtistRepository) GetBySlug(ctx context.Context, slug string) (*model.Artist, error) {
+	return getArtist(ctx, r.db, sq.Eq{"slug": slug})
+}
+
+func getArtist(ctx context.Context, q sqlx.QueryerContext, where sq.Eq) (*model.Artist, error) {
+	var artist model.Artist
+
+	query, args, err := artistSelect().
+		Where(where).
+		ToSql()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.getArtist")
+	}
+
+	err = q.QueryRowxContext(ctx, query, args...).StructScan(&artist)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrArtistNotFound
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.getArtist")
+	}
+
+	return &artist, nil
+}
+
+// Changes canonical name and slug of artist, old name is kept as alias.
+// Names of artist songs are changed too
+func (r *PgArtistRepository) Rename(ctx context.Context, artistID uint64, name string) (*model.Artist, error) {
+	name = normalizeName(name)
+	if len(name) == 0 {
+		return nil, domain.ErrInvalidName
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Rename")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	artist, err := lockArtist(ctx, tx, artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	owner, err := aliasOwner(ctx, tx, aliasKey(name))
+	if err != nil {
+		return nil, err
+	}
+
+	if owner != 0 && owner != artistID {
+		return nil, errors.Wrapf(domain.ErrAliasTaken, "%q", name)
+	}
+
+	// Slug follows name unless only case or spaces are changed
+	slug := artist.Slug
+	if aliasKey(name) != aliasKey(artist.Name) {
+		slug, err = uniqueSlug(ctx, tx, name)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	_, err = sq.StatementBuilder.
+		Update("artists").
+		Set("updated_at", time.Now()).
+		Set("name", name).
+		Set("slug", slug).
+		Where(sq.Eq{
+			"id": artistID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Rename")
+	}
+
+	err = addAlias(ctx, tx, artistID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setSongsArtist(ctx, tx, artistID, artistID, name, domain.ChangeNote{
+		Author: domain.SystemAuthor,
+		Reason: fmt.Sprintf("artist renamed to %s", name),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	artist, err = getArtist(ctx, tx, sq.Eq{"id": artistID})
+	if err != nil {
+		return nil, err
+	}
+
+	return artist, commitUnique(tx, "repository.Rename")
+}
+
+// Adds aliases to artist. Artists already known by any of aliases are merged into this one:
+// their songs and aliases are moved and they are removed
+func (r *PgArtistRepository) MergeAliases(ctx context.Context, artistID uint64, aliases []string) (*model.Artist, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.MergeAliases")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	artist, err := lockArtist(ctx, tx, artistID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alias := range aliases {
+		alias = normalizeName(alias)
+		if len(alias) == 0 {
+			return nil, domain.ErrInvalidName
+		}
+
+		owner, err := aliasOwner(ctx, tx, aliasKey(alias))
+		if err != nil {
+			return nil, err
+		}
+
+		if owner == artistID {
+			continue
+		}
+
+		if owner != 0 {
+			err = mergeArtist(ctx, tx, owner, artist)
+			if err != nil {
+				return nil, err
+			}
+
+			continue
+		}
+
+		err = addAlias(ctx, tx, artistID, alias)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	artist, err = getArtist(ctx, tx, sq.Eq{"id": artistID})
+	if err != nil {
+		return nil, err
+	}
+
+	return artist, commitUnique(tx, "repository.MergeAliases")
+}
+
+// Moves songs, credits, albums and aliases of artist to target artist and removes it
+func mergeArtist(ctx context.Context, tx *sqlx.Tx, artistID uint64, target *model.Artist) error {
+	// Credits are moved first so song revisions see them
+	err := moveCredits(ctx, tx, artistID, target.ID)
+	if err != nil {
+		return err
+	}
+
+	err = setSongsArtist(ctx, tx, artistID, target.ID, target.Name, domain.ChangeNote{
+		Author: domain.SystemAuthor,
+		Reason: fmt.Sprintf("artist merged into %s", target.Name),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.StatementBuilder.
+		Update("albums").
+		Set("updated_at", time.Now()).
+		Set("artist_id", target.ID).
+		Where(sq.Eq{
+			"artist_id": artistID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.mergeArtist")
+	}
+
+	_, err = sq.StatementBuilder.
+		Update("artist_aliases").
+		Set("artist_id", target.ID).
+		Where(sq.Eq{
+			"artist_id": artistID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.mergeArtist")
+	}
+
+	_, err = sq.StatementBuilder.
+		Delete("artists").
+		Where(sq.Eq{
+			"id": artistID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	return errors.Wrap(err, "repository.mergeArtist")
+}
+
+// Removes artist without songs, credits and albums
+func (r *PgArtistRepository) Delete(ctx context.Context, artistID uint64) error {
+	var songs, albums uint64
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "repository.Delete")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	_, err = lockArtist(ctx, tx, artistID)
+	if err != nil {
+		return err
+	}
+
+	// Songs crediting artist in any role are counted
+	err = tx.QueryRowxContext(ctx,
+		`SELECT (SELECT count(DISTINCT song_id) FROM song_credits WHERE artist_id = $1),
+			(SELECT count(*) FROM albums WHERE artist_id = $1)`,
+		artistID,
+	).Scan(&songs, &albums)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.Delete")
+	}
+
+	if songs > 0 || albums > 0 {
+		return errors.Wrapf(domain.ErrArtistHasSongs, "%d songs, %d albums", songs, albums)
+	}
+
+	_, err = sq.StatementBuilder.
+		Delete("artists").
+		Where(sq.Eq{
+			"id": artistID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.Delete")
+	}
+
+	return errors.Wrap(tx.Commit(), "repository.Delete")
+}
+
+// Takes artist names lock and locks artist row until end of transaction
+func lockArtist(ctx context.Context, tx *sqlx.Tx, artistID uint64) (*model.Artist, error) {
+	var artist model.Artist
+
+	err := lockArtistNames(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowxContext(ctx,
+		"SELECT id, name, slug FROM artists WHERE id = $1 FOR UPDATE",
+		artistID,
+	).Scan(&artist.ID, &artist.Name, &artist.Slug)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrArtistNotFound
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.lockArtist")
+	}
+
+	return &artist, nil
+}
+
+// Takes artist names lock until end of transaction, taking it again in same transaction is no-op
+func lockArtistNames(ctx context.Context, tx *sqlx.Tx) error {
+	_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", artistLockKey)
+
+	return errors.Wrap(err, "repository.lockArtistNames")
+}
+
+// Finds artist song group name resolves to, unknown name creates new artist
+func resolveArtist(ctx context.Context, tx *sqlx.Tx, name string) (*model.Artist, error) {
+	name = normalizeName(name)
+	if len(name) == 0 {
+		return nil, domain.ErrInvalidName
+	}
+
+	artist, err := artistByAlias(ctx, tx, aliasKey(name))
+	if !errors.Is(err, domain.ErrArtistNotFound) {
+		return artist, err
+	}
+
+	err = lockArtistNames(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Artist could be created while lock was awaited
+	artist, err = artistByAlias(ctx, tx, aliasKey(name))
+	if !errors.Is(err, domain.ErrArtistNotFound) {
+		return artist, err
+	}
+
+	artist = &model.Artist{Name: name}
+
+	artist.Slug, err = uniqueSlug(ctx, tx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowxContext(ctx,
+		"INSERT INTO artists (name, slug) VALUES ($1, $2) RETURNING id",
+		artist.Name, artist.Slug,
+	).Scan(&artist.ID)
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.resolveArtist")
+	}
+
+	return artist, addAlias(ctx, tx, artist.ID, name)
+}
+
+func artistByAlias(ctx context.Context, tx *sqlx.Tx, key string) (*model.Artist, error) {
+	var artist model.Artist
+
+	err := tx.QueryRowxContext(ctx,
+		"SELECT a.id, a.name, a.slug FROM artist_aliases al JOIN artists a ON a.id = al.artist_id WHERE al.alias_key = $1",
+		key,
+	).Scan(&artist.ID, &artist.Name, &artist.Slug)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrArtistNotFound
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.artistByAlias")
+	}
+
+	return &artist, nil
+}
+
+// Returns ID of artist known by alias, zero if alias is free
+func aliasOwner(ctx context.Context, tx *sqlx.Tx, key string) (uint64, error) {
+	artist, err := artistByAlias(ctx, tx, key)
+
+	if errors.Is(err, domain.ErrArtistNotFound) {
+		return 0, nil
+	}
+
+	if err != nil {
+		return 0, err
+	}
+
+	return artist.ID, nil
+}
+
+// Adds alias to artist, spelling of existing alias is updated
+func addAlias(ctx context.Context, tx *sqlx.Tx, artistID uint64, alias string) error {
+	_, err := sq.StatementBuilder.
+		Insert("artist_aliases").
+		Columns("alias_key", "alias", "artist_id").
+		Values(aliasKey(alias), alias, artistID).
+		Suffix("ON CONFLICT (alias_key) DO UPDATE SET alias = EXCLUDED.alias, artist_id = EXCLUDED.artist_id").
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	return errors.Wrap(err, "repository.addAlias")
+}
+
+// Moves songs of artist to another one, or renames them when artist is the same.
+// Revision of each changed song is saved
+func setSongsArtist(ctx context.Context, tx *sqlx.Tx, artistID, targetID uint64, name string, note domain.ChangeNote) error {
+	var changed []uint64
+
+	query, args, err := sq.StatementBuilder.
+		Update("songs").
+		Set("updated_at", time.Now()).
+		Set("version", sq.Expr("version + 1")).
+		Set("artist_id", targetID).
+		Set("song_group", name).
+		Where(sq.Eq{
+			"artist_id": artistID,
+		}).
+		Where(sq.Or{
+			sq.NotEq{"artist_id": targetID},
+			sq.NotEq{"song_group": name},
+		}).
+		Suffix("RETURNING id").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if err != nil {
+		return errors.Wrap(err, "repository.setSongsArtist")
+	}
+
+	err = tx.SelectContext(ctx, &changed, query, args...)
+	if err != nil {
+		return errors.Wrap(err, "repository.setSongsArtist")
+	}
+
+	for _, songID := range changed {
+		err = recordRevision(ctx, tx, songID, note, true)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns free slug made of name
+func uniqueSlug(ctx context.Context, tx *sqlx.Tx, name string) (string, error) {
+	base := slugify(name)
+	if len(base) == 0 {
+		base = "artist"
+	}
+
+	for n := 1; ; n++ {
+		var taken bool
+
+		slug := base
+		if n > 1 {
+			slug += "-" + strconv.Itoa(n)
+		}
+
+		err := tx.QueryRowxContext(ctx, "SELECT EXISTS (SELECT 1 FROM artists WHERE slug = $1)", slug).Scan(&taken)
+		if err != nil {
+			return "", errors.Wrap(err, "repository.uniqueSlug")
+		}
+
+		if !taken {
+			return slug, nil
+		}
+	}
+}
+
+// Trims name and collapses spaces in it
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// Returns key aliases are compared by, names differing in case and spaces share key
+func aliasKey(name string) string {
+	return strings.ToLower(normalizeName(name))
+}
+
+// Makes lowercase URL part of name, non alphanumeric runs are replaced with dash.
+// Empty for names without letters and digits
+func slugify(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	return strings.Join(words, "-")
+}
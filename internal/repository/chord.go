@@ -0,0 +1,91 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Fetches ChordPro source of song
+func (r *PgSongRepository) GetChordSheet(ctx context.Context, songID uint64) (string, error) {
+	var source string
+
+	// Chord sheet of song in trash is hidden
+	err := r.db.QueryRowxContext(ctx, `SELECT c.source FROM song_chord_sheets c
+		JOIN songs s ON s.id = c.song_id
+		WHERE c.song_id = $1 AND s.deleted_at IS NULL`,
+		songID,
+	).Scan(&source)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", missingChordSheet(ctx, r.db, songID)
+	}
+
+	if err != nil {
+		return "", errors.Wrap(err, "repository.GetChordSheet")
+	}
+
+	return source, nil
+}
+
+// Stores ChordPro source of song, previous one is replaced
+func (r *PgSongRepository) SetChordSheet(ctx context.Context, songID uint64, source string) error {
+	res, err := r.db.ExecContext(ctx, `INSERT INTO song_chord_sheets (song_id, source)
+		SELECT id, $2 FROM songs WHERE id = $1 AND deleted_at IS NULL
+		ON CONFLICT (song_id) DO UPDATE SET source = EXCLUDED.source, updated_at = $3`,
+		songID, source, time.Now(),
+	)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.SetChordSheet")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "repository.SetChordSheet")
+	}
+
+	if affected == 0 {
+		return domain.ErrSongNotFound
+	}
+
+	return nil
+}
+
+// Removes chord sheet of song
+func (r *PgSongRepository) DeleteChordSheet(ctx context.Context, songID uint64) error {
+	// Chord sheet of song in trash is kept for restore
+	res, err := r.db.ExecContext(ctx, `DELETE FROM song_chord_sheets c
+		USING songs s
+		WHERE c.song_id = $1 AND s.id = c.song_id AND s.deleted_at IS NULL`,
+		songID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "repository.DeleteChordSheet")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "repository.DeleteChordSheet")
+	}
+
+	if affected == 0 {
+		return missingChordSheet(ctx, r.db, songID)
+	}
+
+	return nil
+}
+
+// Tells missing song from song without chord sheet
+func missingChordSheet(ctx context.Context, q sqlx.QueryerContext, songID uint64) error {
+	err := checkSongExists(ctx, q, songID)
+	if err != nil {
+		return err
+	}
+
+	return domain.ErrNoChordSheet
+}
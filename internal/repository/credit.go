@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"slices"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Makes artist the only primary credit of song
+func setPrimaryCredit(ctx context.Context, tx *sqlx.Tx, songID, artistID uint64) error {
+	_, err := tx.ExecContext(ctx,
+		"DELETE FROM song_credits WHERE song_id = $1 AND role = $2 AND artist_id <> $3",
+		songID, model.CreditPrimary, artistID,
+	)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.setPrimaryCredit")
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO song_credits (song_id, artist_id, role) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING",
+		songID, artistID, model.CreditPrimary,
+	)
+
+	return errors.Wrap(err, "repository.setPrimaryCredit")
+}
+
+// Replaces credits of song besides primary one, artists are resolved by name.
+// Primary credits in list are skipped, primary credit follows song group
+func setCredits(ctx context.Context, tx *sqlx.Tx, songID uint64, credits model.Credits) error {
+	_, err := tx.ExecContext(ctx,
+		"DELETE FROM song_credits WHERE song_id = $1 AND role <> $2",
+		songID, model.CreditPrimary,
+	)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.setCredits")
+	}
+
+	ib := sq.StatementBuilder.
+		Insert("song_credits").
+		Columns("song_id", "artist_id", "role").
+		Suffix("ON CONFLICT DO NOTHING").
+		PlaceholderFormat(sq.Dollar)
+
+	var added int
+
+	for _, credit := range credits {
+		if credit.Role == model.CreditPrimary {
+			continue
+		}
+
+		if !slices.Contains(model.CreditRoles, credit.Role) {
+			return errors.Wrapf(domain.ErrCreditRole, "%q", credit.Role)
+		}
+
+		artist, err := resolveArtist(ctx, tx, credit.Artist)
+		if err != nil {
+			return err
+		}
+
+		ib = ib.Values(songID, artist.ID, credit.Role)
+		added++
+	}
+
+	if added == 0 {
+		return nil
+	}
+
+	_, err = ib.RunWith(tx).ExecContext(ctx)
+
+	return errors.Wrap(err, "repository.setCredits")
+}
+
+// Moves credits of artist to target artist, credits target already has are dropped
+func moveCredits(ctx context.Context, tx *sqlx.Tx, artistID, targetID uint64) error {
+	_, err := tx.ExecContext(ctx,
+		`DELETE FROM song_credits c WHERE c.artist_id = $1 AND EXISTS (
+			SELECT 1 FROM song_credits t WHERE t.song_id = c.song_id AND t.role = c.role AND t.artist_id = $2)`,
+		artistID, targetID,
+	)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.moveCredits")
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE song_credits SET artist_id = $2 WHERE artist_id = $1",
+		artistID, targetID,
+	)
+
+	return errors.Wrap(err, "repository.moveCredits")
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/pkg/errors"
+)
+
+// Column or expression songs are ordered by
+type sortKey struct {
+	// Name used in cursor signature
+	name string
+	// Song field key value is read from, computed keys have none
+	field string
+	// Ordered expression, compared with cursor values
+	expr sq.Sqlizer
+	// SQL type of cursor value
+	cast string
+	desc bool
+	// Formats key value of song for cursor
+	value func(song *model.Song) string
+}
+
+// Keyset position encoded in opaque cursor
+type cursor struct {
+	// Sort keys signature, cursor is only valid for the same ordering
+	Sort   string   `json:"s"`
+	Values []string `json:"v"`
+	// Cursor points to page before position
+	Before bool `json:"b,omitempty"`
+}
+
+var idSortKey = sortKey{
+	name:  "id",
+	field: "id",
+	expr:  sq.Expr("id"),
+	cast:  "bigint",
+	desc:  true,
+	value: func(song *model.Song) string {
+		return strconv.FormatUint(song.ID, 10)
+	},
+}
+
+// Song fields list can be sorted by, names as they appear in requests
+var sortFields = map[string]sortKey{
+	"name":         textSortKey("name", model.FieldName, "song_name", func(song *model.Song) string { return song.Name }),
+	"group":        textSortKey("group", model.FieldGroup, "song_group", func(song *model.Song) string { return song.Group }),
+	"release_date": releaseDateSortKey,
+	"created_at":   timeSortKey("created_at", func(song *model.Song) time.Time { return song.CreatedAt }),
+	"updated_at":   timeSortKey("updated_at", func(song *model.Song) time.Time { return song.UpdatedAt }),
+}
+
+// Songs without release date go before dated ones in ascending order
+var releaseDateSortKey = sortKey{
+	name:  "release_date",
+	field: model.FieldReleaseDate,
+	expr:  sq.Expr("coalesce(release_date, '-infinity'::date)"),
+	cast:  "date",
+	value: func(song *model.Song) string {
+		if song.ReleaseDate == nil {
+			return "-infinity"
+		}
+
+		return song.ReleaseDate.Format(time.DateOnly)
+	},
+}
+
+func textSortKey(name, field, column string, value func(song *model.Song) string) sortKey {
+	return sortKey{
+		name:  name,
+		field: field,
+		expr:  sq.Expr(column),
+		cast:  "text",
+		value: value,
+	}
+}
+
+func timeSortKey(column string, field func(song *model.Song) time.Time) sortKey {
+	return sortKey{
+		name:  column,
+		field: column,
+		expr:  sq.Expr(column),
+		cast:  "timestamp",
+		value: func(song *model.Song) string {
+			return field(song).Format(time.RFC3339Nano)
+		},
+	}
+}
+
+// Builds sort key of computed float column
+func floatSortKey(name string, expr sq.Sqlizer, field func(song *model.Song) *float64) sortKey {
+	return sortKey{
+		name: name,
+		expr: expr,
+		cast: "float8",
+		desc: true,
+		value: func(song *model.Song) string {
+			if v := field(song); v != nil {
+				return strconv.FormatFloat(*v, 'g', -1, 64)
+			}
+
+			return "0"
+		},
+	}
+}
+
+// Parses requested sort fields, "-" prefix means descending order
+func parseSort(sort []string) ([]sortKey, error) {
+	keys := make([]sortKey, 0, len(sort))
+
+	for _, field := range sort {
+		name, desc := strings.CutPrefix(field, "-")
+
+		key, ok := sortFields[name]
+		if !ok {
+			return nil, errors.Wrapf(domain.ErrSortField, "%q", name)
+		}
+
+		key.desc = desc
+		keys = append(keys, key)
+	}
+
+	return keys, nil
+}
+
+func sortSignature(keys []sortKey) string {
+	parts := make([]string, len(keys))
+
+	for i, key := range keys {
+		if key.desc {
+			parts[i] = "-" + key.name
+		} else {
+			parts[i] = key.name
+		}
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// Returns cursor pointing to song position
+func songCursor(keys []sortKey, song *model.Song, before bool) *cursor {
+	c := cursor{
+		Sort:   sortSignature(keys),
+		Values: make([]string, len(keys)),
+		Before: before,
+	}
+
+	for i, key := range keys {
+		c.Values[i] = key.value(song)
+	}
+
+	return &c
+}
+
+// Returns cursor pointing to the same position in opposite direction
+func (c cursor) reversed() *cursor {
+	c.Before = !c.Before
+
+	return &c
+}
+
+func (c cursor) encode() *string {
+	// Marshaling of strings can't fail
+	data, _ := json.Marshal(c)
+	encoded := base64.RawURLEncoding.EncodeToString(data)
+
+	return &encoded
+}
+
+func decodeCursor(encoded string, keys []sortKey) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, errors.Wrap(domain.ErrInvalidCursor, err.Error())
+	}
+
+	var c cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, errors.Wrap(domain.ErrInvalidCursor, err.Error())
+	}
+
+	if c.Sort != sortSignature(keys) || len(c.Values) != len(keys) {
+		return nil, errors.Wrap(domain.ErrInvalidCursor, "cursor was issued for different ordering")
+	}
+
+	// Values are compared in SQL, they must be valid for key type
+	for i, key := range keys {
+		if !validCursorValue(key.cast, c.Values[i]) {
+			return nil, errors.Wrapf(domain.ErrInvalidCursor, "invalid %s value %q", key.name, c.Values[i])
+		}
+	}
+
+	return &c, nil
+}
+
+// Reports whether cursor value can be cast to SQL type
+func validCursorValue(cast, value string) bool {
+	var err error
+
+	switch cast {
+	case "bigint":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "float8":
+		var f float64
+		f, err = strconv.ParseFloat(value, 64)
+		if err == nil && (math.IsNaN(f) || math.IsInf(f, 0)) {
+			return false
+		}
+	case "date":
+		if value != "-infinity" {
+			_, err = time.Parse(time.DateOnly, value)
+		}
+	case "timestamp":
+		_, err = time.Parse(time.RFC3339Nano, value)
+	case "text":
+		return utf8.ValidString(value) && !strings.ContainsRune(value, 0)
+	}
+
+	return err == nil
+}
+
+// Builds condition selecting rows after cursor position, or before it if cursor says so
+func keysetCondition(keys []sortKey, c *cursor) sq.Sqlizer {
+	or := sq.Or{}
+
+	for i, key := range keys {
+		and := sq.And{}
+
+		for j := 0; j < i; j++ {
+			and = append(and, sq.Expr("? = ?::"+keys[j].cast, keys[j].expr, c.Values[j]))
+		}
+
+		op := ">"
+		if key.desc != c.Before {
+			op = "<"
+		}
+
+		and = append(and, sq.Expr("? "+op+" ?::"+key.cast, key.expr, c.Values[i]))
+		or = append(or, and)
+	}
+
+	return or
+}
+
+// Adds ORDER BY clauses of sort keys, reversed when paging backwards
+func orderBySortKeys(sb sq.SelectBuilder, keys []sortKey, reverse bool) sq.SelectBuilder {
+	for _, key := range keys {
+		direction := " ASC"
+		if key.desc != reverse {
+			direction = " DESC"
+		}
+
+		sb = sb.OrderByClause(sq.Expr("?"+direction, key.expr))
+	}
+
+	return sb
+}
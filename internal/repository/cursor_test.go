@@ -0,0 +1,326 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+)
+
+func testSortKeys(t *testing.T, sort ...string) []sortKey {
+	t.Helper()
+
+	keys, err := parseSort(sort)
+	if err != nil {
+		t.Fatalf("parse sort: %v", err)
+	}
+
+	return append(keys, idSortKey)
+}
+
+func rawCursor(data string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(data))
+}
+
+func testRankKey() sortKey {
+	return floatSortKey("rank", sq.Expr("rank"), func(song *model.Song) *float64 { return song.Rank })
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	released := time.Date(2006, 7, 16, 0, 0, 0, 0, time.UTC)
+	rank := 0.0759
+
+	song := &model.Song{
+		ID:          42,
+		Name:        "Supermassive Black Hole",
+		Group:       "Muse",
+		ReleaseDate: &released,
+		CreatedAt:   time.Date(2024, 1, 2, 3, 4, 5, 678900000, time.UTC),
+		UpdatedAt:   time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3*3600)),
+		Rank:        &rank,
+	}
+
+	tests := []struct {
+		name   string
+		keys   []sortKey
+		song   *model.Song
+		before bool
+		want   []string
+	}{
+		{
+			name: "id only",
+			keys: []sortKey{idSortKey},
+			song: &model.Song{ID: 42},
+			want: []string{"42"},
+		},
+		{
+			name:   "id only paging backwards",
+			keys:   []sortKey{idSortKey},
+			song:   &model.Song{ID: 42},
+			before: true,
+			want:   []string{"42"},
+		},
+		{
+			name:   "text keys paging backwards",
+			keys:   testSortKeys(t, "group", "-name"),
+			song:   song,
+			before: true,
+			want:   []string{"Muse", "Supermassive Black Hole", "42"},
+		},
+		{
+			name: "time keys",
+			keys: testSortKeys(t, "-release_date", "created_at", "updated_at"),
+			song: song,
+			want: []string{"2006-07-16", "2024-01-02T03:04:05.6789Z", "2024-01-02T03:04:05+03:00", "42"},
+		},
+		{
+			name: "unknown release date",
+			keys: testSortKeys(t, "release_date"),
+			song: &model.Song{ID: 7},
+			want: []string{"-infinity", "7"},
+		},
+		{
+			name: "computed key",
+			keys: []sortKey{testRankKey(), idSortKey},
+			song: song,
+			want: []string{"0.0759", "42"},
+		},
+		{
+			name: "computed key without value",
+			keys: []sortKey{testRankKey(), idSortKey},
+			song: &model.Song{ID: 7},
+			want: []string{"0", "7"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded := songCursor(tt.keys, tt.song, tt.before).encode()
+
+			c, err := decodeCursor(*encoded, tt.keys)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+
+			if !reflect.DeepEqual(c.Values, tt.want) {
+				t.Errorf("values = %q, want %q", c.Values, tt.want)
+			}
+
+			if c.Before != tt.before {
+				t.Errorf("before = %v, want %v", c.Before, tt.before)
+			}
+
+			reversed, err := decodeCursor(*c.reversed().encode(), tt.keys)
+			if err != nil {
+				t.Fatalf("decode reversed: %v", err)
+			}
+
+			if reversed.Before == tt.before || !reflect.DeepEqual(reversed.Values, tt.want) {
+				t.Errorf("reversed cursor = %+v", reversed)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	keys := []sortKey{testRankKey(), idSortKey}
+
+	tests := []struct {
+		name   string
+		cursor string
+		keys   []sortKey
+		err    bool
+	}{
+		{
+			name:   "valid",
+			cursor: rawCursor(`{"s":"-rank,-id","v":["0.5","1"]}`),
+			keys:   keys,
+		},
+		{
+			name:   "valid backwards",
+			cursor: rawCursor(`{"s":"-rank,-id","v":["0.5","1"],"b":true}`),
+			keys:   keys,
+		},
+		{
+			name:   "valid unknown release date",
+			cursor: rawCursor(`{"s":"-release_date,name,created_at,-id","v":["-infinity","Song","2024-01-02T03:04:05Z","1"],"b":true}`),
+			keys:   testSortKeys(t, "-release_date", "name", "created_at"),
+		},
+		{
+			name:   "not base64",
+			cursor: "not a cursor!",
+			keys:   keys,
+			err:    true,
+		},
+		{
+			name:   "padded base64",
+			cursor: base64.URLEncoding.EncodeToString([]byte(`{"s":"-id","v":["12"]}`)),
+			keys:   []sortKey{idSortKey},
+			err:    true,
+		},
+		{
+			name:   "not json",
+			cursor: rawCursor(`42`),
+			keys:   []sortKey{idSortKey},
+			err:    true,
+		},
+		{
+			name:   "truncated json",
+			cursor: rawCursor(`{"s":"-id","v":["1"`),
+			keys:   []sortKey{idSortKey},
+			err:    true,
+		},
+		{
+			name:   "values of wrong type",
+			cursor: rawCursor(`{"s":"-id","v":[1]}`),
+			keys:   []sortKey{idSortKey},
+			err:    true,
+		},
+		{
+			name:   "different ordering",
+			cursor: rawCursor(`{"s":"-id","v":["1"]}`),
+			keys:   keys,
+			err:    true,
+		},
+		{
+			name:   "different direction",
+			cursor: rawCursor(`{"s":"name,-id","v":["Song","1"]}`),
+			keys:   testSortKeys(t, "-name"),
+			err:    true,
+		},
+		{
+			name:   "missing values",
+			cursor: rawCursor(`{"s":"-rank,-id","v":["0.5"]}`),
+			keys:   keys,
+			err:    true,
+		},
+		{
+			name:   "extra values",
+			cursor: rawCursor(`{"s":"-id","v":["1","2"]}`),
+			keys:   []sortKey{idSortKey},
+			err:    true,
+		},
+		{
+			name:   "invalid id",
+			cursor: rawCursor(`{"s":"-id","v":["1; DROP TABLE songs"]}`),
+			keys:   []sortKey{idSortKey},
+			err:    true,
+		},
+		{
+			name:   "invalid date",
+			cursor: rawCursor(`{"s":"-release_date,name,created_at,-id","v":["16.07.2006","Song","2024-01-02T03:04:05Z","1"]}`),
+			keys:   testSortKeys(t, "-release_date", "name", "created_at"),
+			err:    true,
+		},
+		{
+			name:   "invalid timestamp",
+			cursor: rawCursor(`{"s":"-release_date,name,created_at,-id","v":["2006-07-16","Song","yesterday","1"]}`),
+			keys:   testSortKeys(t, "-release_date", "name", "created_at"),
+			err:    true,
+		},
+		{
+			name:   "text with null byte",
+			cursor: rawCursor(`{"s":"-release_date,name,created_at,-id","v":["2006-07-16","So\u0000ng","2024-01-02T03:04:05Z","1"]}`),
+			keys:   testSortKeys(t, "-release_date", "name", "created_at"),
+			err:    true,
+		},
+		{
+			name:   "float not a number",
+			cursor: rawCursor(`{"s":"-rank,-id","v":["NaN","1"]}`),
+			keys:   keys,
+			err:    true,
+		},
+		{
+			name:   "empty",
+			cursor: "",
+			keys:   []sortKey{idSortKey},
+			err:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeCursor(tt.cursor, tt.keys)
+
+			if tt.err && !errors.Is(err, domain.ErrInvalidCursor) {
+				t.Fatalf("error = %v, want %v", err, domain.ErrInvalidCursor)
+			}
+
+			if !tt.err && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestKeysetCondition(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []sortKey
+		values []string
+		before bool
+		want   string
+	}{
+		{
+			name:   "after position",
+			keys:   []sortKey{testRankKey(), idSortKey},
+			values: []string{"0.5", "42"},
+			want:   "((rank < ?::float8) OR (rank = ?::float8 AND id < ?::bigint))",
+		},
+		{
+			name:   "before position",
+			keys:   []sortKey{testRankKey(), idSortKey},
+			values: []string{"0.5", "42"},
+			before: true,
+			want:   "((rank > ?::float8) OR (rank = ?::float8 AND id > ?::bigint))",
+		},
+		{
+			name:   "ascending text key",
+			keys:   testSortKeys(t, "name"),
+			values: []string{"Song", "42"},
+			want:   "((song_name > ?::text) OR (song_name = ?::text AND id < ?::bigint))",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &cursor{Values: tt.values, Before: tt.before}
+
+			sql, args, err := keysetCondition(tt.keys, c).ToSql()
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if sql != tt.want {
+				t.Errorf("sql = %q, want %q", sql, tt.want)
+			}
+
+			wantArgs := []any{tt.values[0], tt.values[0], tt.values[1]}
+			if !reflect.DeepEqual(args, wantArgs) {
+				t.Errorf("args = %v, want %v", args, wantArgs)
+			}
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	keys, err := parseSort([]string{"-release_date", "name"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := sortSignature(keys); got != "-release_date,name" {
+		t.Errorf("signature = %q, want %q", got, "-release_date,name")
+	}
+
+	for _, sort := range [][]string{{"rank"}, {"id"}, {"--name"}, {""}} {
+		if _, err := parseSort(sort); !errors.Is(err, domain.ErrSortField) {
+			t.Errorf("parseSort(%q) error = %v, want %v", sort, err, domain.ErrSortField)
+		}
+	}
+}
@@ -0,0 +1,271 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Constraint keeping artist and normalized name of live songs unique
+const songNameConstraint = "songs_artist_name_key_excl"
+
+// Pairs of live songs with similar names, scored by similarity of names, groups and lyrics
+const duplicatesQuery = `SELECT * FROM (
+	SELECT
+		a.id AS song_id,
+		a.song_name,
+		a.song_group,
+		b.id AS duplicate_id,
+		b.song_name AS duplicate_name,
+		b.song_group AS duplicate_group,
+		similarity(lower(f_unaccent(a.song_name)), lower(f_unaccent(b.song_name)))::float8 AS name_similarity,
+		CASE WHEN a.artist_id = b.artist_id THEN 1
+			ELSE similarity(lower(f_unaccent(a.song_group)), lower(f_unaccent(b.song_group)))
+		END::float8 AS artist_similarity,
+		CASE WHEN a.song_text <> '' AND b.song_text <> ''
+			THEN similarity(a.song_text, b.song_text)::float8
+		END AS lyrics_similarity
+	FROM songs a
+	JOIN songs b ON b.id > a.id AND lower(f_unaccent(a.song_name)) % lower(f_unaccent(b.song_name))
+	WHERE a.deleted_at IS NULL AND b.deleted_at IS NULL
+) c
+CROSS JOIN LATERAL (
+	SELECT ((c.name_similarity + c.artist_similarity + coalesce(c.lyrics_similarity, 0))
+		/ CASE WHEN c.lyrics_similarity IS NULL THEN 2 ELSE 3 END)::float8 AS score
+) s
+ORDER BY s.score DESC, c.song_id, c.duplicate_id
+LIMIT $1`
+
+// Fetches pairs of songs whose names are at least threshold similar, most likely duplicates first
+func (r *PgSongRepository) FindDuplicates(ctx context.Context, threshold float64, limit uint) ([]domain.DuplicateCandidate, error) {
+	candidates := make([]domain.DuplicateCandidate, 0)
+
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.FindDuplicates")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Threshold of % operator, setting is local to transaction
+	_, err = tx.ExecContext(ctx,
+		"SELECT set_config('pg_trgm.similarity_threshold', $1, true)",
+		strconv.FormatFloat(threshold, 'f', -1, 64),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.FindDuplicates")
+	}
+
+	err = tx.SelectContext(ctx, &candidates, duplicatesQuery, limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.FindDuplicates")
+	}
+
+	return candidates, errors.Wrap(tx.Commit(), "repository.FindDuplicates")
+}
+
+// Merges duplicate into song: albums, credits, genres, tags and revisions of duplicate are moved to song,
+// synchronized lyrics and chord sheet are moved when song has none. Duplicate is removed
+// and its ID redirects to song. Non-zero version must match current song version
+func (r *PgSongRepository) Merge(
+	ctx context.Context,
+	songID, duplicateID, version uint64,
+	note domain.ChangeNote,
+) (*model.Song, error) {
+	var song model.Song
+
+	if songID == duplicateID {
+		return nil, domain.ErrMergeSelf
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Merge")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	err = lockSongVersion(ctx, tx, songID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Duplicate in trash can be merged too
+	var locked uint64
+
+	err = tx.QueryRowxContext(ctx, "SELECT id FROM songs WHERE id = $1 FOR UPDATE", duplicateID).Scan(&locked)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrapf(domain.ErrSongNotFound, "id %d", duplicateID)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Merge")
+	}
+
+	statements := []string{
+		// Albums listing both songs keep position of song
+		`UPDATE album_tracks t SET song_id = $1 WHERE t.song_id = $2
+			AND NOT EXISTS (SELECT 1 FROM album_tracks k WHERE k.album_id = t.album_id AND k.song_id = $1)`,
+		// Tracks left on duplicate are on albums with song, following tracks of disc move up
+		`WITH removed AS (
+				DELETE FROM album_tracks WHERE song_id = $2 RETURNING album_id, disc_number, track_number
+			)
+			UPDATE album_tracks t SET track_number = t.track_number - 1
+			FROM removed d
+			WHERE t.album_id = d.album_id AND t.disc_number = d.disc_number AND t.track_number > d.track_number`,
+		`INSERT INTO song_credits (song_id, artist_id, role)
+			SELECT $1, artist_id, role FROM song_credits WHERE song_id = $2 AND role <> 'primary'
+			ON CONFLICT DO NOTHING`,
+		`INSERT INTO song_genres (song_id, genre_id)
+			SELECT $1, genre_id FROM song_genres WHERE song_id = $2
+			ON CONFLICT DO NOTHING`,
+		`UPDATE song_timed_lines SET song_id = $1 WHERE song_id = $2
+			AND NOT EXISTS (SELECT 1 FROM song_timed_lines WHERE song_id = $1)`,
+		`UPDATE song_chord_sheets SET song_id = $1 WHERE song_id = $2
+			AND NOT EXISTS (SELECT 1 FROM song_chord_sheets WHERE song_id = $1)`,
+		// Tags of duplicate follow tags of song
+		`UPDATE songs s SET tags = ARRAY(
+				SELECT t FROM unnest(s.tags || d.tags) WITH ORDINALITY u(t, n) GROUP BY t ORDER BY min(n)
+			)
+			FROM songs d WHERE s.id = $1 AND d.id = $2`,
+		`UPDATE song_redirects SET target_id = $1 WHERE target_id = $2`,
+		`INSERT INTO song_redirects (song_id, target_id) VALUES ($2, $1)`,
+	}
+
+	for _, statement := range statements {
+		_, err = tx.ExecContext(ctx, statement, songID, duplicateID)
+		if err != nil {
+			return nil, errors.Wrap(err, "repository.Merge")
+		}
+	}
+
+	err = mergeRevisions(ctx, tx, songID, duplicateID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Relations left on duplicate are removed with it
+	_, err = tx.ExecContext(ctx, "DELETE FROM songs WHERE id = $1", duplicateID)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Merge")
+	}
+
+	// Song stops being exempt from uniqueness once no other copy is left
+	_, err = tx.ExecContext(ctx, `UPDATE songs s SET unique_exempt = false
+		WHERE s.id = $1 AND s.unique_exempt AND NOT EXISTS (
+			SELECT 1 FROM songs d
+			WHERE d.artist_id = s.artist_id AND d.name_key = s.name_key AND d.id <> s.id
+				AND d.deleted_at IS NULL AND NOT d.unique_exempt
+		)`,
+		songID,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Merge")
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE songs SET version = version + 1, updated_at = $2 WHERE id = $1",
+		songID, time.Now(),
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Merge")
+	}
+
+	err = recordRevision(ctx, tx, songID, note, true)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := songSelect().
+		Where(sq.Eq{
+			"id": songID,
+		}).
+		ToSql()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Merge")
+	}
+
+	err = tx.QueryRowxContext(ctx, query, args...).StructScan(&song)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Merge")
+	}
+
+	return &song, commitUnique(tx, "repository.Merge")
+}
+
+// Moves revisions of duplicate to song, they are numbered in their order after revisions of song
+func mergeRevisions(ctx context.Context, tx *sqlx.Tx, songID, duplicateID uint64) error {
+	_, err := tx.ExecContext(ctx, `UPDATE song_revisions r SET song_id = $1, revision = m.last + o.n
+		FROM (
+			SELECT revision, row_number() OVER (ORDER BY revision) AS n
+			FROM song_revisions WHERE song_id = $2
+		) o, (
+			SELECT coalesce(max(revision), 0) AS last FROM song_revisions WHERE song_id = $1
+		) m
+		WHERE r.song_id = $2 AND r.revision = o.revision`,
+		songID, duplicateID,
+	)
+
+	return errors.Wrap(err, "repository.mergeRevisions")
+}
+
+// Returns ID of song merged song redirects to
+func (r *PgSongRepository) RedirectTarget(ctx context.Context, songID uint64) (uint64, error) {
+	var target uint64
+
+	err := r.db.QueryRowxContext(ctx, "SELECT target_id FROM song_redirects WHERE song_id = $1", songID).Scan(&target)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, domain.ErrSongNotFound
+	}
+
+	if err != nil {
+		return 0, errors.Wrap(err, "repository.RedirectTarget")
+	}
+
+	return target, nil
+}
+
+// Reports live song with same artist and normalized name as song.
+// Duplicates existing before uniqueness was introduced are exempt until merged
+func checkDuplicate(ctx context.Context, q sqlx.QueryerContext, songID uint64) error {
+	var existingID uint64
+
+	err := q.QueryRowxContext(ctx, `SELECT d.id FROM songs s
+		JOIN songs d ON d.artist_id = s.artist_id AND d.name_key = s.name_key AND d.id <> s.id
+		WHERE s.id = $1 AND s.deleted_at IS NULL AND NOT s.unique_exempt
+			AND d.deleted_at IS NULL AND NOT d.unique_exempt
+		ORDER BY d.id LIMIT 1`,
+		songID,
+	).Scan(&existingID)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "repository.checkDuplicate")
+	}
+
+	return &domain.DuplicateSongError{ExistingID: existingID}
+}
+
+// Commits transaction, song uniqueness violated by concurrent change is reported as duplicate song
+func commitUnique(tx *sqlx.Tx, op string) error {
+	err := tx.Commit()
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.ConstraintName == songNameConstraint {
+		return errors.Wrap(domain.ErrDuplicateSong, op)
+	}
+
+	return errors.Wrap(err, op)
+}
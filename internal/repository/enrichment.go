@@ -0,0 +1,314 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/lyrics"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Revision note of details found by enrichment workers
+var enrichmentNote = domain.ChangeNote{
+	Author: domain.SystemAuthor,
+	Reason: "metadata enrichment",
+}
+
+// Handles claimed enrichment job. Handler fills in song details and status,
+// pending status means job should be retried after job.RetryIn
+type EnrichmentHandler func(ctx context.Context, job *model.EnrichmentJob) error
+
+// Song enrichment queue storage
+type EnrichmentRepository interface {
+	ProcessNext(ctx context.Context, handle EnrichmentHandler) (bool, error)
+	GetState(ctx context.Context, songID uint64) (*model.EnrichmentState, error)
+}
+
+type PgEnrichmentRepository struct {
+	db *sqlx.DB
+}
+
+func NewPgEnrichmentRepository(db *sqlx.DB) *PgEnrichmentRepository {
+	return &PgEnrichmentRepository{
+		db: db,
+	}
+}
+
+// Claimed job is hidden from other workers for lease time, it's picked up again
+// after lease if worker stops before saving outcome
+const enrichmentLease = 10 * time.Minute
+
+// Claims next due job skipping ones claimed by other workers and passes it to handler
+// outside of transaction, then stores its outcome. Returns false if queue has no due jobs
+func (r *PgEnrichmentRepository) ProcessNext(ctx context.Context, handle EnrichmentHandler) (bool, error) {
+	job, claimedUntil, err := r.claimNext(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if job == nil {
+		return false, nil
+	}
+
+	err = handle(ctx, job)
+	if err != nil {
+		// Interrupted job is made due again, shutdown cancels ctx so it can't be used
+		releaseCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+		defer cancel()
+
+		_, releaseErr := r.db.ExecContext(releaseCtx,
+			"UPDATE enrichment_jobs SET run_at = $3 WHERE id = $1 AND run_at = $2",
+			job.ID, claimedUntil, job.RunAt,
+		)
+		if releaseErr != nil {
+			err = errors.Wrapf(err, "release job: %v", releaseErr)
+		}
+
+		return false, errors.Wrap(err, "enrichment handler")
+	}
+
+	err = r.saveOutcome(ctx, job, claimedUntil)
+	if err != nil {
+		return false, errors.Wrap(err, "repository.ProcessNext")
+	}
+
+	return true, nil
+}
+
+// Locks next due job of song which isn't in trash and moves it past lease.
+// Returns nil job when queue has no due jobs
+func (r *PgEnrichmentRepository) claimNext(ctx context.Context) (*model.EnrichmentJob, time.Time, error) {
+	var (
+		job          model.EnrichmentJob
+		claimedUntil time.Time
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, claimedUntil, errors.Wrap(err, "repository.claimNext")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	sb := sq.Select(
+		"j.id",
+		"j.song_id",
+		"j.attempts",
+		"j.run_at",
+		"j.last_error",
+		"s.song_name",
+		"s.song_group",
+	).
+		From("enrichment_jobs j").
+		Join("songs s ON s.id = j.song_id").
+		Where("j.run_at <= NOW()").
+		Where(sq.Eq{
+			"s.deleted_at": nil,
+		}).
+		OrderBy("j.run_at", "j.id").
+		Limit(1).
+		Suffix("FOR UPDATE OF j SKIP LOCKED").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := sb.ToSql()
+	if err != nil {
+		return nil, claimedUntil, errors.Wrap(err, "repository.claimNext")
+	}
+
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(
+		&job.ID,
+		&job.SongID,
+		&job.Attempts,
+		&job.RunAt,
+		&job.LastError,
+		&job.Song.Name,
+		&job.Song.Group,
+	)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, claimedUntil, nil
+	}
+
+	if err != nil {
+		return nil, claimedUntil, errors.Wrap(err, "repository.claimNext")
+	}
+
+	err = tx.QueryRowxContext(ctx,
+		"UPDATE enrichment_jobs SET run_at = NOW() + make_interval(secs => $2) WHERE id = $1 RETURNING run_at",
+		job.ID, enrichmentLease.Seconds(),
+	).Scan(&claimedUntil)
+	if err != nil {
+		return nil, claimedUntil, errors.Wrap(err, "repository.claimNext")
+	}
+
+	job.Song.ID = job.SongID
+	job.Song.EnrichmentStatus = model.EnrichmentPending
+
+	return &job, claimedUntil, errors.Wrap(tx.Commit(), "repository.claimNext")
+}
+
+// Stores song details or reschedules job. Outcome is dropped when job was claimed again after lease,
+// song in trash keeps its job until it's restored
+func (r *PgEnrichmentRepository) saveOutcome(ctx context.Context, job *model.EnrichmentJob, claimedUntil time.Time) error {
+	var (
+		locked    uint64
+		deletedAt *time.Time
+	)
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	err = tx.QueryRowxContext(ctx,
+		"SELECT id FROM enrichment_jobs WHERE id = $1 AND run_at = $2 FOR UPDATE",
+		job.ID, claimedUntil,
+	).Scan(&locked)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if job.Song.EnrichmentStatus == model.EnrichmentPending {
+		_, err := sq.StatementBuilder.
+			Update("enrichment_jobs").
+			Set("attempts", job.Attempts).
+			Set("run_at", sq.Expr("NOW() + make_interval(secs => ?)", job.RetryIn.Seconds())).
+			Set("last_error", job.LastError).
+			Where(sq.Eq{
+				"id": job.ID,
+			}).
+			PlaceholderFormat(sq.Dollar).
+			RunWith(tx).
+			ExecContext(ctx)
+
+		if err != nil {
+			return err
+		}
+
+		return tx.Commit()
+	}
+
+	// Fields could be set by hand during lookup
+	err = tx.QueryRowxContext(ctx,
+		"SELECT manual_fields, deleted_at FROM songs WHERE id = $1 FOR UPDATE",
+		job.SongID,
+	).Scan(&job.Song.ManualFields, &deletedAt)
+
+	if err != nil {
+		return err
+	}
+
+	if deletedAt != nil {
+		return nil
+	}
+
+	ub := sq.StatementBuilder.
+		Update("songs").
+		Set("updated_at", time.Now()).
+		Set("enrichment_status", job.Song.EnrichmentStatus).
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{
+			"id": job.SongID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx)
+
+	// Fields set by hand while song was pending are kept
+	if job.Song.EnrichmentStatus == model.EnrichmentEnriched {
+		if !job.Song.ManualFields.Has(model.FieldText) {
+			text := lyrics.Normalize(job.Song.Text)
+
+			err := setSections(ctx, tx, job.SongID, text)
+			if err != nil {
+				return err
+			}
+
+			ub = ub.Set("song_text", text)
+		}
+
+		if !job.Song.ManualFields.Has(model.FieldLink) {
+			ub = ub.Set("link", job.Song.Link)
+		}
+	}
+
+	// Songs without found date take it from their albums
+	if !job.Song.ManualFields.Has(model.FieldReleaseDate) {
+		if job.Song.ReleaseDate != nil {
+			ub = ub.Set("release_date", job.Song.ReleaseDate)
+		} else {
+			ub = ub.Set("release_date", sq.Expr("coalesce(release_date, "+albumReleaseDate+")"))
+		}
+	}
+
+	_, err = ub.ExecContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = recordRevision(ctx, tx, job.SongID, enrichmentNote, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.StatementBuilder.
+		Delete("enrichment_jobs").
+		Where(sq.Eq{
+			"id": job.ID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Fetches song enrichment status along with its queued job if any
+func (r *PgEnrichmentRepository) GetState(ctx context.Context, songID uint64) (*model.EnrichmentState, error) {
+	var state model.EnrichmentState
+
+	sb := sq.Select(
+		"s.id",
+		"s.enrichment_status",
+		"j.attempts",
+		"j.run_at",
+		"j.last_error",
+	).
+		From("songs s").
+		LeftJoin("enrichment_jobs j ON j.song_id = s.id").
+		Where(sq.Eq{
+			"s.id":         songID,
+			"s.deleted_at": nil,
+		}).
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := sb.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.GetState")
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&state)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSongNotFound
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.GetState")
+	}
+
+	return &state, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Counts songs matching filter per genre, tag, artist and release year, at most limit values of each
+func (r *PgSongRepository) Facets(ctx context.Context, filter domain.SongFilter, limit uint) (*domain.SongFacets, error) {
+	matched, err := applySongFilter(sq.Select("id").From("songs"), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	inMatched := sq.Expr("s.id IN (?)", matched)
+
+	result := &domain.SongFacets{
+		Genres:       make([]domain.FacetCount, 0),
+		Tags:         make([]domain.FacetCount, 0),
+		Artists:      make([]domain.FacetCount, 0),
+		ReleaseYears: make([]domain.FacetCount, 0),
+	}
+
+	genres := sq.Select("g.slug AS value", "g.name AS label", "count(*) AS count").
+		From("songs s").
+		Join("song_genres sg ON sg.song_id = s.id").
+		Join("genres g ON g.id = sg.genre_id").
+		Where(inMatched).
+		GroupBy("g.slug", "g.name").
+		OrderBy("count DESC", "g.name")
+
+	tags := sq.Select("t AS value", "t AS label", "count(*) AS count").
+		From("songs s, unnest(s.tags) t").
+		Where(inMatched).
+		GroupBy("t").
+		OrderBy("count DESC", "t")
+
+	artists := sq.Select("a.id::text AS value", "a.name AS label", "count(*) AS count").
+		From("songs s").
+		Join("artists a ON a.id = s.artist_id").
+		Where(inMatched).
+		GroupBy("a.id", "a.name").
+		OrderBy("count DESC", "a.name")
+
+	years := sq.Select(
+		"extract(year FROM s.release_date)::int::text AS value",
+		"extract(year FROM s.release_date)::int::text AS label",
+		"count(*) AS count",
+	).
+		From("songs s").
+		Where(sq.NotEq{"s.release_date": nil}).
+		Where(inMatched).
+		GroupBy("1").
+		OrderBy("1 DESC")
+
+	facets := []struct {
+		query  sq.SelectBuilder
+		counts *[]domain.FacetCount
+	}{
+		{genres, &result.Genres},
+		{tags, &result.Tags},
+		{artists, &result.Artists},
+		{years, &result.ReleaseYears},
+	}
+
+	err = r.readFiltered(ctx, filter, func(tx *sqlx.Tx) error {
+		for _, facet := range facets {
+			query, args, err := facet.query.
+				Limit(uint64(limit)).
+				PlaceholderFormat(sq.Dollar).
+				ToSql()
+
+			if err != nil {
+				return err
+			}
+
+			err = tx.SelectContext(ctx, facet.counts, query, args...)
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Facets")
+	}
+
+	return result, nil
+}
@@ -0,0 +1,205 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Longest allowed tag in characters
+const maxTagLength = 64
+
+// Genre slugs of song ordered by name
+const genresColumn = `(SELECT coalesce(jsonb_agg(g.slug ORDER BY g.name), '[]')
+	FROM song_genres sg JOIN genres g ON g.id = sg.genre_id WHERE sg.song_id = songs.id) AS genres`
+
+// Genre vocabulary repository
+type GenreRepository interface {
+	List(ctx context.Context) (model.Genres, error)
+	Create(ctx context.Context, name string) (*model.Genre, error)
+	Delete(ctx context.Context, genreID uint64) error
+}
+
+type PgGenreRepository struct {
+	db *sqlx.DB
+}
+
+func NewPgGenreRepository(db *sqlx.DB) *PgGenreRepository {
+	return &PgGenreRepository{
+		db: db,
+	}
+}
+
+// Returns query selecting genres with number of songs
+func genreSelect() sq.SelectBuilder {
+	return sq.Select(
+		"id",
+		"created_at",
+		"name",
+		"slug",
+		`(SELECT count(*) FROM song_genres sg JOIN songs s ON s.id = sg.song_id
+			WHERE sg.genre_id = genres.id AND s.deleted_at IS NULL) AS song_count`,
+	).
+		From("genres").
+		PlaceholderFormat(sq.Dollar)
+}
+
+// Fetches all genres ordered by name
+func (r *PgGenreRepository) List(ctx context.Context) (model.Genres, error) {
+	genres := make(model.Genres, 0)
+
+	query, args, err := genreSelect().
+		OrderBy("name").
+		ToSql()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.List")
+	}
+
+	err = r.db.SelectContext(ctx, &genres, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.List")
+	}
+
+	return genres, nil
+}
+
+// Adds genre to vocabulary, slug is made of name and must be free
+func (r *PgGenreRepository) Create(ctx context.Context, name string) (*model.Genre, error) {
+	genre := model.Genre{
+		Name: normalizeName(name),
+	}
+
+	genre.Slug = slugify(genre.Name)
+	if len(genre.Slug) == 0 {
+		return nil, domain.ErrInvalidName
+	}
+
+	err := r.db.QueryRowxContext(ctx,
+		"INSERT INTO genres (name, slug) VALUES ($1, $2) ON CONFLICT (slug) DO NOTHING RETURNING id, created_at",
+		genre.Name, genre.Slug,
+	).Scan(&genre.ID, &genre.CreatedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errors.Wrapf(domain.ErrGenreExists, "%q", genre.Slug)
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Create")
+	}
+
+	return &genre, nil
+}
+
+// Removes genre which isn't set on any song
+func (r *PgGenreRepository) Delete(ctx context.Context, genreID uint64) error {
+	var inUse bool
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "repository.Delete")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	err = tx.QueryRowxContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM song_genres WHERE genre_id = genres.id) FROM genres WHERE id = $1 FOR UPDATE",
+		genreID,
+	).Scan(&inUse)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrGenreNotFound
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "repository.Delete")
+	}
+
+	if inUse {
+		return domain.ErrGenreInUse
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM genres WHERE id = $1", genreID)
+	if err != nil {
+		return errors.Wrap(err, "repository.Delete")
+	}
+
+	return errors.Wrap(tx.Commit(), "repository.Delete")
+}
+
+// Replaces genres of song, all slugs must be known
+func setGenres(ctx context.Context, tx *sqlx.Tx, songID uint64, slugs []string) error {
+	var genreIDs []uint64
+
+	_, err := tx.ExecContext(ctx, "DELETE FROM song_genres WHERE song_id = $1", songID)
+	if err != nil {
+		return errors.Wrap(err, "repository.setGenres")
+	}
+
+	if len(slugs) == 0 {
+		return nil
+	}
+
+	slugs = normalizeTags(slugs)
+
+	err = tx.SelectContext(ctx, &genreIDs, "SELECT id FROM genres WHERE slug = ANY($1::text[]) ORDER BY slug", slugs)
+	if err != nil {
+		return errors.Wrap(err, "repository.setGenres")
+	}
+
+	if len(genreIDs) < len(slugs) {
+		var known []string
+
+		err = tx.SelectContext(ctx, &known, "SELECT slug FROM genres WHERE slug = ANY($1::text[])", slugs)
+		if err != nil {
+			return errors.Wrap(err, "repository.setGenres")
+		}
+
+		for _, slug := range slugs {
+			if !slices.Contains(known, slug) {
+				return errors.Wrapf(domain.ErrGenreNotFound, "%q", slug)
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO song_genres (song_id, genre_id) SELECT $1, unnest($2::int[])",
+		songID, genreIDs,
+	)
+
+	return errors.Wrap(err, "repository.setGenres")
+}
+
+// Lowercases tags and collapses spaces in them, duplicates are dropped
+func normalizeTags(tags []string) []string {
+	result := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.ToLower(normalizeName(tag))
+
+		if len(tag) > 0 && !slices.Contains(result, tag) {
+			result = append(result, tag)
+		}
+	}
+
+	return result
+}
+
+// Normalizes tags of song and checks their length
+func songTags(tags []string) ([]string, error) {
+	for _, tag := range tags {
+		length := utf8.RuneCountInString(normalizeName(tag))
+		if length == 0 || length > maxTagLength {
+			return nil, errors.Wrapf(domain.ErrInvalidTag, "%q", tag)
+		}
+	}
+
+	return normalizeTags(tags), nil
+}
@@ -0,0 +1,155 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Fetches page of song revisions made before revision in cursor, newest first. Snapshots are left out
+func (r *PgSongRepository) ListRevisions(ctx context.Context, songID uint64, cursor string, limit uint) (*domain.RevisionPage, error) {
+	revisions := make(model.SongRevisions, 0)
+
+	// Extra revision tells whether there are more revisions
+	sb := sq.Select("revision", "song_id", "song_version", "created_at", "author", "reason").
+		From("song_revisions").
+		Where(sq.Eq{
+			"song_id": songID,
+		}).
+		OrderBy("revision DESC").
+		Limit(uint64(limit) + 1).
+		PlaceholderFormat(sq.Dollar)
+
+	if len(cursor) > 0 {
+		before, err := strconv.ParseUint(cursor, 10, 64)
+		if err != nil {
+			return nil, errors.Wrap(domain.ErrInvalidCursor, err.Error())
+		}
+
+		sb = sb.Where(sq.Lt{
+			"revision": before,
+		})
+	}
+
+	query, args, err := sb.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.ListRevisions")
+	}
+
+	err = r.db.SelectContext(ctx, &revisions, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.ListRevisions")
+	}
+
+	// Every song has revisions, none means there is no song
+	if len(revisions) == 0 && len(cursor) == 0 {
+		return nil, domain.ErrSongNotFound
+	}
+
+	page := &domain.RevisionPage{
+		Limit: limit,
+	}
+
+	if uint(len(revisions)) > limit {
+		revisions = revisions[:limit]
+		next := strconv.FormatUint(revisions[len(revisions)-1].Revision, 10)
+		page.NextCursor = &next
+	}
+
+	page.Revisions = revisions
+
+	return page, nil
+}
+
+// Fetches song revision with its snapshot
+func (r *PgSongRepository) GetRevision(ctx context.Context, songID, revision uint64) (*model.SongRevision, error) {
+	var rev model.SongRevision
+
+	query, args, err := sq.Select("revision", "song_id", "song_version", "created_at", "author", "reason", "snapshot").
+		From("song_revisions").
+		Where(sq.Eq{
+			"song_id":  songID,
+			"revision": revision,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.GetRevision")
+	}
+
+	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&rev)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrRevisionNotFound
+	}
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.GetRevision")
+	}
+
+	return &rev, nil
+}
+
+// Saves current fields of song as its next revision. Song row must be locked by transaction.
+// Unless forced, nothing is saved when fields are same as in latest revision
+func recordRevision(ctx context.Context, tx *sqlx.Tx, songID uint64, note domain.ChangeNote, force bool) error {
+	var song model.Song
+
+	query, args, err := songSelect().
+		Where(sq.Eq{
+			"id": songID,
+		}).
+		ToSql()
+
+	if err != nil {
+		return errors.Wrap(err, "repository.recordRevision")
+	}
+
+	err = tx.QueryRowxContext(ctx, query, args...).StructScan(&song)
+	if err != nil {
+		return errors.Wrap(err, "repository.recordRevision")
+	}
+
+	snapshot := model.NewSongSnapshot(&song)
+
+	if !force {
+		var latest model.SongSnapshot
+
+		err = tx.QueryRowxContext(ctx,
+			"SELECT snapshot FROM song_revisions WHERE song_id = $1 ORDER BY revision DESC LIMIT 1",
+			songID,
+		).Scan(&latest)
+
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return errors.Wrap(err, "repository.recordRevision")
+		}
+
+		if err == nil && sameSnapshot(snapshot, &latest) {
+			return nil
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO song_revisions (song_id, revision, song_version, author, reason, snapshot)
+		VALUES ($1, (SELECT coalesce(max(revision), 0) + 1 FROM song_revisions WHERE song_id = $1), $2, $3, $4, $5)`,
+		songID, song.Version, note.Author, note.Reason, snapshot,
+	)
+
+	return errors.Wrap(err, "repository.recordRevision")
+}
+
+// Compares snapshots by their stored form
+func sameSnapshot(a, b *model.SongSnapshot) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && bytes.Equal(encodedA, encodedB)
+}
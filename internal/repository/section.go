@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/lyrics"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+)
+
+// Replaces lyrics sections of song with ones parsed from text
+func setSections(ctx context.Context, tx *sqlx.Tx, songID uint64, text string) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM song_sections WHERE song_id = $1", songID)
+	if err != nil {
+		return errors.Wrap(err, "repository.setSections")
+	}
+
+	sections := lyrics.Parse(text)
+	if len(sections) == 0 {
+		return nil
+	}
+
+	ib := sq.StatementBuilder.
+		Insert("song_sections").
+		Columns("song_id", "section_index", "section_type", "label", "lines", "repeat_of").
+		PlaceholderFormat(sq.Dollar)
+
+	for _, section := range sections {
+		ib = ib.Values(songID, section.Index, section.Type, section.Label, []string(section.Lines), section.RepeatOf)
+	}
+
+	_, err = ib.RunWith(tx).ExecContext(ctx)
+
+	return errors.Wrap(err, "repository.setSections")
+}
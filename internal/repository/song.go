@@ -2,27 +2,93 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	sq "github.com/Masterminds/squirrel"
 	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/lyrics"
 	"github.com/Sadere/song-depository/internal/model"
+	"github.com/Sadere/song-depository/internal/search"
 	"github.com/jmoiron/sqlx"
 	"github.com/pkg/errors"
 )
 
-const (
-	SongsPerPage = 10
-)
+// Search hit snippet settings
+const headlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5"
+
+// Number of lyrics sections
+const verseCountColumn = "(SELECT count(*) FROM song_sections ss WHERE ss.song_id = songs.id) AS verse_count"
+
+// Credited artists ordered by role and name
+const creditsColumn = `(SELECT coalesce(jsonb_agg(jsonb_build_object('artist_id', a.id, 'artist', a.name, 'role', c.role)
+		ORDER BY array_position(ARRAY['primary', 'featuring', 'composer', 'lyricist', 'producer'], c.role), a.name), '[]')
+	FROM song_credits c JOIN artists a ON a.id = c.artist_id WHERE c.song_id = songs.id) AS credits`
+
+// Song fields and SQL expressions selecting them
+var songFieldColumns = map[string]string{
+	"id":                   "id",
+	"created_at":           "created_at",
+	"updated_at":           "updated_at",
+	"version":              "version",
+	model.FieldName:        "song_name",
+	model.FieldGroup:       "song_group",
+	"artist_id":            "artist_id",
+	model.FieldCredits:     creditsColumn,
+	model.FieldGenres:      genresColumn,
+	model.FieldTags:        "to_jsonb(tags) AS tags",
+	model.FieldText:        "song_text",
+	model.FieldReleaseDate: "release_date",
+	model.FieldLink:        "link",
+	"enrichment_status":    "enrichment_status",
+	"manual_fields":        "manual_fields",
+	"deleted_at":           "deleted_at",
+	model.FieldTextLength:  "char_length(song_text) AS text_length",
+	model.FieldVerseCount:  verseCountColumn,
+}
+
+// Escapes LIKE wildcards in user input
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// Text search configurations and their tsvector columns
+var searchColumns = map[string]string{
+	"english": "search_english",
+	"russian": "search_russian",
+	"simple":  "search_simple",
+}
 
 // Song storage repository
 type SongRepository interface {
-	Create(ctx context.Context, song *model.Song) error
-	GetById(ctx context.Context, songID uint64) (*model.Song, error)
-	ListFiltered(ctx context.Context, filter domain.SongFilter, page uint) (model.Songs, error)
-	GetSongText(ctx context.Context, songID uint64) (string, error)
-	Update(ctx context.Context, songID uint64, req domain.UpdateSongRequest) error
-	Delete(ctx context.Context, songID uint64) error
+	Create(ctx context.Context, song *model.Song, note domain.ChangeNote) error
+	GetById(ctx context.Context, songID uint64, fields model.FieldSet) (*model.Song, error)
+	ListFiltered(ctx context.Context, filter domain.SongFilter, opts domain.ListOptions) (*domain.SongPage, error)
+	Facets(ctx context.Context, filter domain.SongFilter, limit uint) (*domain.SongFacets, error)
+	GetSections(ctx context.Context, songID uint64) (model.Sections, error)
+	GetTimedLines(ctx context.Context, songID uint64) (model.TimedLines, error)
+	SetTimedLines(ctx context.Context, songID uint64, lines model.TimedLines) error
+	GetChordSheet(ctx context.Context, songID uint64) (string, error)
+	SetChordSheet(ctx context.Context, songID uint64, source string) error
+	DeleteChordSheet(ctx context.Context, songID uint64) error
+	ListRevisions(ctx context.Context, songID uint64, cursor string, limit uint) (*domain.RevisionPage, error)
+	GetRevision(ctx context.Context, songID, revision uint64) (*model.SongRevision, error)
+	Update(ctx context.Context, songID, version uint64, req domain.UpdateSongRequest, note domain.ChangeNote) (uint64, error)
+	UpdateDetail(ctx context.Context, song *model.Song, note domain.ChangeNote) error
+	Replace(
+		ctx context.Context,
+		songID, version uint64,
+		note domain.ChangeNote,
+		modify func(song *model.Song) error,
+	) (*model.Song, error)
+	Delete(ctx context.Context, songID, version uint64) error
+	Restore(ctx context.Context, songID uint64) error
+	HardDelete(ctx context.Context, songID uint64) error
+	Purge(ctx context.Context, before time.Time, limit uint) (int64, error)
+	FindDuplicates(ctx context.Context, threshold float64, limit uint) ([]domain.DuplicateCandidate, error)
+	Merge(ctx context.Context, songID, duplicateID, version uint64, note domain.ChangeNote) (*model.Song, error)
+	RedirectTarget(ctx context.Context, songID uint64) (uint64, error)
 }
 
 type PgSongRepository struct {
@@ -35,45 +101,149 @@ func NewPgSongRepository(db *sqlx.DB) *PgSongRepository {
 	}
 }
 
-// Creates new song in DB
-func (r *PgSongRepository) Create(ctx context.Context, song *model.Song) error {
+// Creates new song in DB with its first revision, group is resolved to artist through aliases.
+// Pending songs are queued for enrichment
+func (r *PgSongRepository) Create(ctx context.Context, song *model.Song, note domain.ChangeNote) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "repository.Create")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	artist, err := resolveArtist(ctx, tx, song.Group)
+	if err != nil {
+		return err
+	}
+
+	song.ArtistID = artist.ID
+	song.Group = artist.Name
+	song.Text = lyrics.Normalize(song.Text)
+
 	sb := sq.StatementBuilder.
 		Insert("songs").
-		Columns("song_name", "song_group", "song_text", "release_date", "link").
-		PlaceholderFormat(sq.Dollar).
-		RunWith(r.db)
+		Columns("song_name", "song_group", "artist_id", "song_text", "release_date", "link", "enrichment_status").
+		Suffix("RETURNING id, version").
+		PlaceholderFormat(sq.Dollar)
 
 	sb = sb.Values(
 		song.Name,
 		song.Group,
+		song.ArtistID,
 		song.Text,
 		song.ReleaseDate,
 		song.Link,
+		song.EnrichmentStatus,
 	)
 
-	_, err := sb.ExecContext(ctx)
+	query, args, err := sb.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "repository.Create")
+	}
 
-	return err
-}
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&song.ID, &song.Version)
+	if err != nil {
+		return errors.Wrap(err, "repository.Create")
+	}
 
-func (r *PgSongRepository) GetById(ctx context.Context, songID uint64) (*model.Song, error) {
-	var song model.Song
+	err = checkDuplicate(ctx, tx, song.ID)
+	if err != nil {
+		return err
+	}
+
+	err = setPrimaryCredit(ctx, tx, song.ID, song.ArtistID)
+	if err != nil {
+		return err
+	}
+
+	err = setSections(ctx, tx, song.ID, song.Text)
+	if err != nil {
+		return err
+	}
+
+	err = recordRevision(ctx, tx, song.ID, note, true)
+	if err != nil {
+		return err
+	}
 
-	sb := sq.Select(
+	if song.EnrichmentStatus == model.EnrichmentPending {
+		_, err = sq.StatementBuilder.
+			Insert("enrichment_jobs").
+			Columns("song_id").
+			Values(song.ID).
+			PlaceholderFormat(sq.Dollar).
+			RunWith(tx).
+			ExecContext(ctx)
+
+		if err != nil {
+			return errors.Wrap(err, "repository.Create")
+		}
+	}
+
+	return commitUnique(tx, "repository.Create")
+}
+
+// Returns query selecting all song columns
+func songSelect() sq.SelectBuilder {
+	return sq.Select(
 		"id",
 		"created_at",
 		"updated_at",
+		"version",
 		"song_name",
 		"song_group",
+		"artist_id",
+		creditsColumn,
+		genresColumn,
+		"to_jsonb(tags) AS tags",
 		"song_text",
 		"release_date",
 		"link",
+		"enrichment_status",
+		"manual_fields",
+		"deleted_at",
 	).
 		From("songs").
-		Where(sq.Eq{
-			"id": songID,
-		}).
 		PlaceholderFormat(sq.Dollar)
+}
+
+// Returns query selecting requested song fields, or all stored fields when none requested.
+// ID and version are always selected
+func songFieldsSelect(fields model.FieldSet) (sq.SelectBuilder, error) {
+	if len(fields) == 0 {
+		return songSelect(), nil
+	}
+
+	columns := []string{"id", "version"}
+
+	for _, field := range fields {
+		column, ok := songFieldColumns[field]
+		if !ok {
+			return sq.SelectBuilder{}, errors.Wrapf(domain.ErrUnknownField, "%q", field)
+		}
+
+		if field != "id" && field != "version" {
+			columns = append(columns, column)
+		}
+	}
+
+	return sq.Select(columns...).
+		From("songs").
+		PlaceholderFormat(sq.Dollar), nil
+}
+
+// Fetches requested fields of song which isn't in trash, all stored fields when none requested
+func (r *PgSongRepository) GetById(ctx context.Context, songID uint64, fields model.FieldSet) (*model.Song, error) {
+	var song model.Song
+
+	sb, err := songFieldsSelect(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	sb = sb.Where(sq.Eq{
+		"id":         songID,
+		"deleted_at": nil,
+	})
 
 	query, args, err := sb.ToSql()
 	if err != nil {
@@ -82,6 +252,10 @@ func (r *PgSongRepository) GetById(ctx context.Context, songID uint64) (*model.S
 
 	err = r.db.QueryRowxContext(ctx, query, args...).StructScan(&song)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrSongNotFound
+	}
+
 	if err != nil {
 		return nil, errors.Wrap(err, "repository.GetById")
 	}
@@ -89,41 +263,222 @@ func (r *PgSongRepository) GetById(ctx context.Context, songID uint64) (*model.S
 	return &song, nil
 }
 
-// Fetches songs with pagination and filter
-func (r *PgSongRepository) ListFiltered(ctx context.Context, filter domain.SongFilter, page uint) (model.Songs, error) {
-	var songs model.Songs
+// Fetches page of songs matching filter, songs are ordered by relevance and ID
+func (r *PgSongRepository) ListFiltered(ctx context.Context, filter domain.SongFilter, opts domain.ListOptions) (*domain.SongPage, error) {
+	var c *cursor
 
-	offset := uint64(page * SongsPerPage)
+	songs := make(model.Songs, 0)
 
-	sb := sq.Select(
-		"id",
-		"created_at",
-		"updated_at",
-		"song_name",
-		"song_group",
-		"song_text",
-		"release_date",
-		"link",
-	).
-		From("songs").
-		OrderBy("id DESC").
-		Limit(SongsPerPage).
-		Offset(offset).
-		PlaceholderFormat(sq.Dollar)
+	sortKeys, err := parseSort(opts.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sort key values are needed for cursors
+	fields := opts.Fields
+	if len(fields) > 0 {
+		for _, key := range sortKeys {
+			fields = fields.With(key.field)
+		}
+	}
+
+	sb, err := songFieldsSelect(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	sb, err = applySongFilter(sb, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	countQuery, countArgs, err := sb.RemoveColumns().Column("count(*)").ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.ListFiltered")
+	}
+
+	var relevance []sortKey
+
+	if filter.Query != nil {
+		// Query is validated by filter
+		tsQuery, _ := search.ParseQuery(*filter.Query)
+
+		rank := sq.Expr(
+			"ts_rank("+searchColumns[filter.SearchConfig]+", to_tsquery(?::regconfig, ?))::float8",
+			filter.SearchConfig, tsQuery,
+		)
+
+		sb = sb.Column(sq.Alias(rank, "rank"))
+		relevance = append(relevance, floatSortKey("rank", rank, func(song *model.Song) *float64 {
+			return song.Rank
+		}))
+
+		if opts.Highlight {
+			sb = sb.Column(sq.Expr(
+				"ts_headline(?::regconfig, song_text, to_tsquery(?::regconfig, ?), ?) AS headline",
+				filter.SearchConfig, filter.SearchConfig, tsQuery, headlineOptions,
+			))
+		}
+	}
+
+	score, fuzzy := fuzzyScore(filter)
+	if fuzzy {
+		sb = sb.Column(sq.Alias(score, "score"))
+		relevance = append([]sortKey{floatSortKey("score", score, func(song *model.Song) *float64 {
+			return song.Score
+		})}, relevance...)
+	}
+
+	// Requested sort replaces relevance, ID keeps order stable
+	keys := relevance
+	if len(sortKeys) > 0 {
+		keys = sortKeys
+	}
+
+	keys = append(keys, idSortKey)
+
+	if len(opts.Cursor) > 0 {
+		c, err = decodeCursor(opts.Cursor, keys)
+		if err != nil {
+			return nil, err
+		}
+
+		sb = sb.Where(keysetCondition(keys, c))
+	}
+
+	backward := c != nil && c.Before
+
+	// Extra song tells whether there are more songs
+	sb = orderBySortKeys(sb, keys, backward).Limit(uint64(opts.Limit) + 1)
+
+	query, args, err := sb.ToSql()
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.ListFiltered")
+	}
+
+	page := &domain.SongPage{
+		Limit: opts.Limit,
+	}
+
+	err = r.readFiltered(ctx, filter, func(tx *sqlx.Tx) error {
+		err := tx.SelectContext(ctx, &songs, query, args...)
+		if err != nil {
+			return err
+		}
+
+		if opts.Total {
+			page.Total = new(uint64)
+
+			return tx.GetContext(ctx, page.Total, countQuery, countArgs...)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.ListFiltered")
+	}
+
+	more := uint(len(songs)) > opts.Limit
+	if more {
+		songs = songs[:opts.Limit]
+	}
+
+	if backward {
+		slices.Reverse(songs)
+	}
+
+	page.Songs = songs
+
+	if len(songs) == 0 {
+		// Page is past the end, cursor leads back to songs on the other side
+		if backward {
+			page.NextCursor = c.reversed().encode()
+		} else if c != nil {
+			page.PrevCursor = c.reversed().encode()
+		}
+
+		return page, nil
+	}
+
+	first, last := songs[0], songs[len(songs)-1]
+
+	if backward {
+		page.NextCursor = songCursor(keys, last, false).encode()
+
+		if more {
+			page.PrevCursor = songCursor(keys, first, true).encode()
+		}
+
+		return page, nil
+	}
+
+	if more {
+		page.NextCursor = songCursor(keys, last, false).encode()
+	}
+
+	if c != nil {
+		page.PrevCursor = songCursor(keys, first, true).encode()
+	}
+
+	return page, nil
+}
+
+// Adds filter conditions to songs query
+func applySongFilter(sb sq.SelectBuilder, filter domain.SongFilter) (sq.SelectBuilder, error) {
+	if filter.Match != domain.MatchContains && filter.Match != domain.MatchExact && filter.Match != domain.MatchFuzzy {
+		return sb, errors.Wrapf(domain.ErrMatchMode, "%q", filter.Match)
+	}
+
+	if filter.FuzzyThreshold != nil && (*filter.FuzzyThreshold < 0 || *filter.FuzzyThreshold > 1) {
+		return sb, errors.Wrapf(domain.ErrFuzzyThreshold, "%v", *filter.FuzzyThreshold)
+	}
+
+	if filter.Deleted {
+		sb = sb.Where(sq.NotEq{"deleted_at": nil})
+	} else {
+		sb = sb.Where(sq.Eq{"deleted_at": nil})
+	}
 
-	// Filter
 	if filter.Group != nil {
-		sb = sb.Where(sq.Like{
-			"song_group": *filter.Group,
-		})
+		sb = sb.Where(matchName("song_group", filter.Match, *filter.Group))
 	}
 
 	if filter.Name != nil {
-		sb = sb.Where(sq.Like{
-			"song_name": *filter.Name,
+		sb = sb.Where(matchName("song_name", filter.Match, *filter.Name))
+	}
+
+	if filter.ArtistID != nil {
+		sb = sb.Where(sq.Eq{
+			"artist_id": *filter.ArtistID,
 		})
 	}
 
+	if filter.CreditedArtistID != nil {
+		sb = sb.Where("EXISTS (SELECT 1 FROM song_credits c WHERE c.song_id = songs.id AND c.artist_id = ?)",
+			*filter.CreditedArtistID,
+		)
+	}
+
+	if len(filter.Genres) > 0 {
+		sb = sb.Where(`EXISTS (SELECT 1 FROM song_genres sg JOIN genres g ON g.id = sg.genre_id
+			WHERE sg.song_id = songs.id AND g.slug = ANY(?::text[]))`,
+			normalizeTags(filter.Genres),
+		)
+	}
+
+	if len(filter.TagsAny) > 0 {
+		sb = sb.Where("tags && ?::text[]", normalizeTags(filter.TagsAny))
+	}
+
+	if len(filter.TagsAll) > 0 {
+		sb = sb.Where("tags @> ?::text[]", normalizeTags(filter.TagsAll))
+	}
+
+	if len(filter.TagsNone) > 0 {
+		sb = sb.Where("NOT tags && ?::text[]", normalizeTags(filter.TagsNone))
+	}
+
 	if filter.Text != nil {
 		sb = sb.Where(sq.Like{
 			"song_text": "%" + *filter.Text + "%",
@@ -136,98 +491,681 @@ func (r *PgSongRepository) ListFiltered(ctx context.Context, filter domain.SongF
 		})
 	}
 
-	query, args, err := sb.ToSql()
-	if err != nil {
-		return nil, errors.Wrap(err, "repository.ListFiltered")
+	// Ranges
+	if isReversed(filter.ReleaseDateFrom, filter.ReleaseDateTo) {
+		return sb, errors.Wrap(domain.ErrInvalidRange, "release_date_from > release_date_to")
+	}
+
+	if isReversed(filter.CreatedAfter, filter.CreatedBefore) {
+		return sb, errors.Wrap(domain.ErrInvalidRange, "created_after > created_before")
+	}
+
+	if filter.ReleaseDateFrom != nil {
+		sb = sb.Where(sq.GtOrEq{
+			"release_date": *filter.ReleaseDateFrom,
+		})
+	}
+
+	if filter.ReleaseDateTo != nil {
+		sb = sb.Where(sq.LtOrEq{
+			"release_date": *filter.ReleaseDateTo,
+		})
+	}
+
+	if filter.CreatedAfter != nil {
+		sb = sb.Where(sq.Gt{
+			"created_at": *filter.CreatedAfter,
+		})
+	}
+
+	if filter.CreatedBefore != nil {
+		sb = sb.Where(sq.Lt{
+			"created_at": *filter.CreatedBefore,
+		})
+	}
+
+	if filter.UpdatedSince != nil {
+		sb = sb.Where(sq.GtOrEq{
+			"updated_at": *filter.UpdatedSince,
+		})
+	}
+
+	// Full text search
+	if filter.Query != nil {
+		column, ok := searchColumns[filter.SearchConfig]
+		if !ok {
+			return sb, errors.Wrapf(domain.ErrSearchConfig, "%q", filter.SearchConfig)
+		}
+
+		tsQuery, err := search.ParseQuery(*filter.Query)
+		if err != nil {
+			return sb, err
+		}
+
+		sb = sb.Where(column+" @@ to_tsquery(?::regconfig, ?)", filter.SearchConfig, tsQuery)
+	}
+
+	return sb, nil
+}
+
+// Reports whether both range ends are set and start is after end
+func isReversed(from, to *time.Time) bool {
+	return from != nil && to != nil && from.After(*to)
+}
+
+// Builds case and accent insensitive condition on name column
+func matchName(column string, mode string, value string) sq.Sqlizer {
+	normalized := "lower(f_unaccent(" + column + "))"
+
+	switch mode {
+	case domain.MatchExact:
+		return sq.Expr(normalized+" = lower(f_unaccent(?))", value)
+	case domain.MatchFuzzy:
+		// Uses pg_trgm.similarity_threshold set for transaction
+		return sq.Expr(normalized+" % lower(f_unaccent(?))", value)
+	default:
+		return sq.Expr(normalized+` LIKE '%' || lower(f_unaccent(?)) || '%' ESCAPE '\'`, likeEscaper.Replace(value))
 	}
+}
 
-	// Fetch query
-	err = r.db.SelectContext(ctx, &songs, query, args...)
+// Averaged similarity of fuzzy matched name columns
+func fuzzyScore(filter domain.SongFilter) (sq.Sqlizer, bool) {
+	var (
+		parts []string
+		args  []any
+	)
+
+	if filter.Group != nil {
+		parts = append(parts, "similarity(lower(f_unaccent(song_group)), lower(f_unaccent(?)))")
+		args = append(args, *filter.Group)
+	}
+
+	if filter.Name != nil {
+		parts = append(parts, "similarity(lower(f_unaccent(song_name)), lower(f_unaccent(?)))")
+		args = append(args, *filter.Name)
+	}
+
+	if filter.Match != domain.MatchFuzzy || len(parts) == 0 {
+		return nil, false
+	}
+
+	expr := "((" + strings.Join(parts, " + ") + ") / " + strconv.Itoa(len(parts)) + ")::float8"
+
+	return sq.Expr(expr, args...), true
+}
+
+// Runs reads of filtered songs in read-only transaction, trigram similarity threshold
+// is applied to % operator in fuzzy match mode
+func (r *PgSongRepository) readFiltered(ctx context.Context, filter domain.SongFilter, read func(tx *sqlx.Tx) error) error {
+	tx, err := r.db.BeginTxx(ctx, &sql.TxOptions{ReadOnly: true})
 	if err != nil {
-		return nil, errors.Wrap(err, "repository.ListFiltered")
+		return err
 	}
+	defer tx.Rollback() //nolint:errcheck
 
-	if len(songs) == 0 {
-		return nil, errors.Wrap(domain.ErrNoSongs, "repository.ListFiltered")
+	if filter.Match == domain.MatchFuzzy && filter.FuzzyThreshold != nil {
+		// Setting is local to transaction
+		_, err = tx.ExecContext(ctx,
+			"SELECT set_config('pg_trgm.similarity_threshold', $1, true)",
+			strconv.FormatFloat(*filter.FuzzyThreshold, 'f', -1, 64),
+		)
+		if err != nil {
+			return err
+		}
 	}
 
-	return songs, nil
+	err = read(tx)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-// Fetch song text from DB with provided song ID
-func (r *PgSongRepository) GetSongText(ctx context.Context, songID uint64) (string, error) {
-	var songText string
+// Fetches lyrics sections of song in order
+func (r *PgSongRepository) GetSections(ctx context.Context, songID uint64) (model.Sections, error) {
+	sections := make(model.Sections, 0)
 
-	sb := sq.Select(
-		"song_text",
+	err := checkSongExists(ctx, r.db, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := sq.Select(
+		"section_index",
+		"section_type",
+		"label",
+		"to_jsonb(lines) AS lines",
+		"repeat_of",
 	).
-		From("songs").
+		From("song_sections").
 		Where(sq.Eq{
-			"id": songID,
+			"song_id": songID,
 		}).
-		PlaceholderFormat(sq.Dollar)
+		OrderBy("section_index").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
 
-	query, args, err := sb.ToSql()
 	if err != nil {
-		return "", errors.Wrap(err, "repository.GetSongText")
+		return nil, errors.Wrap(err, "repository.GetSections")
 	}
 
-	err = r.db.QueryRowxContext(ctx, query, args...).Scan(&songText)
-
+	err = r.db.SelectContext(ctx, &sections, query, args...)
 	if err != nil {
-		return "", errors.Wrap(err, "repository.GetSongText")
+		return nil, errors.Wrap(err, "repository.GetSections")
 	}
 
-	return songText, nil
+	return sections, nil
 }
 
-// Updates song in DB
-func (r *PgSongRepository) Update(ctx context.Context, songID uint64, req domain.UpdateSongRequest) error {
+// Updates song in DB and saves its revision, provided fields are marked as set by hand.
+// Credits are replaced when provided. Non-zero version must match current song version. Returns new song version
+func (r *PgSongRepository) Update(
+	ctx context.Context,
+	songID, version uint64,
+	req domain.UpdateSongRequest,
+	note domain.ChangeNote,
+) (uint64, error) {
+	var manualFields model.FieldSet
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, errors.Wrap(err, "repository.Update")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Artists may be resolved below, their lock goes before song row lock
+	if len(req.Group) > 0 || req.Credits != nil {
+		err = lockArtistNames(ctx, tx)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err = lockSongVersion(ctx, tx, songID, version)
+	if err != nil {
+		return 0, err
+	}
+
 	sb := sq.StatementBuilder.
 		Update("songs").
 		Set("updated_at", time.Now()).
+		Set("version", sq.Expr("version + 1")).
 		Where(sq.Eq{
 			"id": songID,
 		}).
-		PlaceholderFormat(sq.Dollar).
-		RunWith(r.db)
+		Suffix("RETURNING version").
+		PlaceholderFormat(sq.Dollar)
 
 	if len(req.Group) > 0 {
-		sb = sb.Set("song_group", req.Group)
+		artist, err := resolveArtist(ctx, tx, req.Group)
+		if err != nil {
+			return 0, err
+		}
+
+		sb = sb.
+			Set("song_group", artist.Name).
+			Set("artist_id", artist.ID)
+
+		manualFields = manualFields.With(model.FieldGroup)
+
+		err = setPrimaryCredit(ctx, tx, songID, artist.ID)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if req.Tags != nil {
+		tags, err := songTags(req.Tags)
+		if err != nil {
+			return 0, err
+		}
+
+		sb = sb.Set("tags", tags)
+	}
+
+	if req.Genres != nil {
+		err = setGenres(ctx, tx, songID, req.Genres)
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	if req.Credits != nil {
+		credits := make(model.Credits, len(req.Credits))
+		for i, credit := range req.Credits {
+			credits[i] = model.Credit{
+				Artist: credit.Artist,
+				Role:   credit.Role,
+			}
+		}
+
+		err = setCredits(ctx, tx, songID, credits)
+		if err != nil {
+			return 0, err
+		}
 	}
 
 	if len(req.Name) > 0 {
 		sb = sb.Set("song_name", req.Name)
+		manualFields = manualFields.With(model.FieldName)
 	}
 
 	if len(req.Text) > 0 {
-		sb = sb.Set("song_text", req.Text)
+		text := lyrics.Normalize(req.Text)
+
+		err = setSections(ctx, tx, songID, text)
+		if err != nil {
+			return 0, err
+		}
+
+		sb = sb.Set("song_text", text)
+		manualFields = manualFields.With(model.FieldText)
 	}
 
 	if req.ReleaseDate != nil {
 		sb = sb.Set("release_date", *req.ReleaseDate)
+		manualFields = manualFields.With(model.FieldReleaseDate)
 	}
 
 	if len(req.Link) > 0 {
 		sb = sb.Set("link", req.Link)
+		manualFields = manualFields.With(model.FieldLink)
+	}
+
+	if len(manualFields) > 0 {
+		sb = sb.Set("manual_fields", sq.Expr(
+			"(SELECT jsonb_agg(DISTINCT f) FROM jsonb_array_elements_text(manual_fields || ?::jsonb) f)",
+			manualFields,
+		))
+	}
+
+	query, args, err := sb.ToSql()
+	if err != nil {
+		return 0, errors.Wrap(err, "repository.Update")
 	}
 
-	_, err := sb.ExecContext(ctx)
+	var newVersion uint64
 
-	return err
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&newVersion)
+	if err != nil {
+		return 0, errors.Wrap(err, "repository.Update")
+	}
+
+	err = checkDuplicate(ctx, tx, songID)
+	if err != nil {
+		return 0, err
+	}
+
+	err = recordRevision(ctx, tx, songID, note, true)
+	if err != nil {
+		return 0, err
+	}
+
+	return newVersion, commitUnique(tx, "repository.Update")
 }
 
-// Removes song from DB
-func (r *PgSongRepository) Delete(ctx context.Context, songID uint64) error {
-	sb := sq.StatementBuilder.
+// Locks song, replaces its editable fields with ones set by modify and saves its revision.
+// Non-zero version must match current song version
+func (r *PgSongRepository) Replace(
+	ctx context.Context,
+	songID, version uint64,
+	note domain.ChangeNote,
+	modify func(song *model.Song) error,
+) (*model.Song, error) {
+	var song model.Song
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Replace")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Artists are resolved below, their lock goes before song row lock
+	err = lockArtistNames(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = lockSongVersion(ctx, tx, songID, version)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := songSelect().
+		Where(sq.Eq{
+			"id": songID,
+		}).
+		ToSql()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Replace")
+	}
+
+	err = tx.QueryRowxContext(ctx, query, args...).StructScan(&song)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Replace")
+	}
+
+	err = modify(&song)
+	if err != nil {
+		return nil, err
+	}
+
+	artist, err := resolveArtist(ctx, tx, song.Group)
+	if err != nil {
+		return nil, err
+	}
+
+	song.ArtistID = artist.ID
+	song.Group = artist.Name
+
+	err = setPrimaryCredit(ctx, tx, songID, song.ArtistID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setCredits(ctx, tx, songID, song.Credits)
+	if err != nil {
+		return nil, err
+	}
+
+	err = setGenres(ctx, tx, songID, song.Genres)
+	if err != nil {
+		return nil, err
+	}
+
+	song.Tags, err = songTags(song.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	song.Text = lyrics.Normalize(song.Text)
+
+	err = setSections(ctx, tx, songID, song.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err = sq.StatementBuilder.
+		Update("songs").
+		Set("updated_at", time.Now()).
+		Set("version", sq.Expr("version + 1")).
+		Set("song_name", song.Name).
+		Set("song_group", song.Group).
+		Set("artist_id", song.ArtistID).
+		Set("song_text", song.Text).
+		Set("release_date", song.ReleaseDate).
+		Set("link", song.Link).
+		Set("manual_fields", song.ManualFields).
+		Set("tags", []string(song.Tags)).
+		Where(sq.Eq{
+			"id": songID,
+		}).
+		Suffix("RETURNING updated_at, version").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Replace")
+	}
+
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&song.UpdatedAt, &song.Version)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Replace")
+	}
+
+	err = checkDuplicate(ctx, tx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.QueryRowxContext(ctx,
+		"SELECT "+creditsColumn+", "+genresColumn+" FROM songs WHERE id = $1",
+		songID,
+	).Scan(&song.Credits, &song.Genres)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.Replace")
+	}
+
+	err = recordRevision(ctx, tx, songID, note, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &song, commitUnique(tx, "repository.Replace")
+}
+
+// Stores song details found by provider, drops queued enrichment of song.
+// Revision is saved when song fields are changed
+func (r *PgSongRepository) UpdateDetail(ctx context.Context, song *model.Song, note domain.ChangeNote) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "repository.UpdateDetail")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	song.Text = lyrics.Normalize(song.Text)
+
+	// Song must not be changed since it was read
+	res, err := sq.StatementBuilder.
+		Update("songs").
+		Set("updated_at", time.Now()).
+		Set("version", sq.Expr("version + 1")).
+		Set("song_text", song.Text).
+		Set("release_date", song.ReleaseDate).
+		Set("link", song.Link).
+		Set("enrichment_status", song.EnrichmentStatus).
+		Set("manual_fields", song.ManualFields).
+		Where(sq.Eq{
+			"id":      song.ID,
+			"version": song.Version,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.UpdateDetail")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "repository.UpdateDetail")
+	}
+
+	if affected == 0 {
+		return domain.ErrVersionMismatch
+	}
+
+	song.Version++
+
+	err = setSections(ctx, tx, song.ID, song.Text)
+	if err != nil {
+		return err
+	}
+
+	err = recordRevision(ctx, tx, song.ID, note, false)
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.StatementBuilder.
+		Delete("enrichment_jobs").
+		Where(sq.Eq{
+			"song_id": song.ID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.UpdateDetail")
+	}
+
+	return errors.Wrap(tx.Commit(), "repository.UpdateDetail")
+}
+
+// Moves song to trash, non-zero version must match current song version
+func (r *PgSongRepository) Delete(ctx context.Context, songID, version uint64) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "repository.Delete")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	err = lockSongVersion(ctx, tx, songID, version)
+	if err != nil {
+		return err
+	}
+
+	_, err = sq.StatementBuilder.
+		Update("songs").
+		Set("deleted_at", time.Now()).
+		Where(sq.Eq{
+			"id": songID,
+		}).
+		PlaceholderFormat(sq.Dollar).
+		RunWith(tx).
+		ExecContext(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.Delete")
+	}
+
+	return errors.Wrap(tx.Commit(), "repository.Delete")
+}
+
+// Takes song out of trash
+func (r *PgSongRepository) Restore(ctx context.Context, songID uint64) error {
+	var deletedAt *time.Time
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "repository.Restore")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	err = tx.QueryRowxContext(ctx, "SELECT deleted_at FROM songs WHERE id = $1 FOR UPDATE", songID).Scan(&deletedAt)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrSongNotFound
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "repository.Restore")
+	}
+
+	if deletedAt == nil {
+		return domain.ErrSongNotDeleted
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE songs SET deleted_at = NULL WHERE id = $1", songID)
+	if err != nil {
+		return errors.Wrap(err, "repository.Restore")
+	}
+
+	// Song added again while this one was in trash
+	err = checkDuplicate(ctx, tx, songID)
+	if err != nil {
+		return err
+	}
+
+	return commitUnique(tx, "repository.Restore")
+}
+
+// Removes song from DB whether it's in trash or not
+func (r *PgSongRepository) HardDelete(ctx context.Context, songID uint64) error {
+	res, err := sq.StatementBuilder.
 		Delete("songs").
 		Where(sq.Eq{
 			"id": songID,
 		}).
 		PlaceholderFormat(sq.Dollar).
-		RunWith(r.db)
+		RunWith(r.db).
+		ExecContext(ctx)
+
+	if err != nil {
+		return errors.Wrap(err, "repository.HardDelete")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "repository.HardDelete")
+	}
+
+	if affected == 0 {
+		return domain.ErrSongNotFound
+	}
+
+	return nil
+}
+
+// Removes at most limit songs moved to trash before given time, returns number of removed songs
+func (r *PgSongRepository) Purge(ctx context.Context, before time.Time, limit uint) (int64, error) {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM songs WHERE id IN (
+		SELECT id FROM songs WHERE deleted_at < $1 ORDER BY deleted_at LIMIT $2
+		FOR UPDATE SKIP LOCKED)`,
+		before, limit,
+	)
+
+	if err != nil {
+		return 0, errors.Wrap(err, "repository.Purge")
+	}
+
+	affected, err := res.RowsAffected()
 
-	_, err := sb.ExecContext(ctx)
+	return affected, errors.Wrap(err, "repository.Purge")
+}
+
+// Locks song row which isn't in trash until end of transaction and checks its version,
+// zero version matches any
+func lockSongVersion(ctx context.Context, tx *sqlx.Tx, songID, version uint64) error {
+	var current uint64
+
+	sb := sq.Select("version").
+		From("songs").
+		Where(sq.Eq{
+			"id":         songID,
+			"deleted_at": nil,
+		}).
+		Suffix("FOR UPDATE").
+		PlaceholderFormat(sq.Dollar)
+
+	query, args, err := sb.ToSql()
+	if err != nil {
+		return errors.Wrap(err, "repository.lockSongVersion")
+	}
+
+	err = tx.QueryRowxContext(ctx, query, args...).Scan(&current)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrSongNotFound
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "repository.lockSongVersion")
+	}
+
+	if version != 0 && version != current {
+		return domain.ErrVersionMismatch
+	}
+
+	return nil
+}
+
+// Reports missing song when there is no song with ID or it's in trash
+func checkSongExists(ctx context.Context, q sqlx.QueryerContext, songID uint64) error {
+	var exists bool
+
+	err := q.QueryRowxContext(ctx, "SELECT EXISTS (SELECT 1 FROM songs WHERE id = $1 AND deleted_at IS NULL)", songID).Scan(&exists)
+	if err != nil {
+		return errors.Wrap(err, "repository.checkSongExists")
+	}
+
+	if !exists {
+		return domain.ErrSongNotFound
+	}
 
-	return err
+	return nil
 }
@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/pkg/errors"
+)
+
+// Fetches synchronized lyrics lines of song in order
+func (r *PgSongRepository) GetTimedLines(ctx context.Context, songID uint64) (model.TimedLines, error) {
+	lines := make(model.TimedLines, 0)
+
+	// Lines of song in trash are hidden
+	query, args, err := sq.Select(
+		"l.line_index",
+		"l.start_ms",
+		"l.end_ms",
+		"l.text",
+		"l.words",
+	).
+		From("song_timed_lines l").
+		Join("songs s ON s.id = l.song_id").
+		Where(sq.Eq{
+			"l.song_id":    songID,
+			"s.deleted_at": nil,
+		}).
+		OrderBy("l.line_index").
+		PlaceholderFormat(sq.Dollar).
+		ToSql()
+
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.GetTimedLines")
+	}
+
+	err = r.db.SelectContext(ctx, &lines, query, args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "repository.GetTimedLines")
+	}
+
+	if len(lines) > 0 {
+		return lines, nil
+	}
+
+	err = checkSongExists(ctx, r.db, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+// Replaces synchronized lyrics of song, empty lines remove them
+func (r *PgSongRepository) SetTimedLines(ctx context.Context, songID uint64, lines model.TimedLines) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "repository.SetTimedLines")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	// Lock song against concurrent uploads
+	var locked uint64
+
+	err = tx.QueryRowxContext(ctx, "SELECT id FROM songs WHERE id = $1 AND deleted_at IS NULL FOR UPDATE", songID).Scan(&locked)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.ErrSongNotFound
+	}
+
+	if err != nil {
+		return errors.Wrap(err, "repository.SetTimedLines")
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM song_timed_lines WHERE song_id = $1", songID)
+	if err != nil {
+		return errors.Wrap(err, "repository.SetTimedLines")
+	}
+
+	if len(lines) > 0 {
+		ib := sq.StatementBuilder.
+			Insert("song_timed_lines").
+			Columns("song_id", "line_index", "start_ms", "end_ms", "text", "words").
+			PlaceholderFormat(sq.Dollar)
+
+		for _, line := range lines {
+			ib = ib.Values(songID, line.Index, line.Start, line.End, line.Text, line.Words)
+		}
+
+		_, err = ib.RunWith(tx).ExecContext(ctx)
+		if err != nil {
+			return errors.Wrap(err, "repository.SetTimedLines")
+		}
+	}
+
+	return errors.Wrap(tx.Commit(), "repository.SetTimedLines")
+}
@@ -0,0 +1,168 @@
+// Translates user search queries into Postgres tsquery syntax
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+)
+
+var ErrInvalidQuery = errors.New("invalid search query")
+
+// Converts search query to to_tsquery input. Supported syntax:
+//
+//	word       documents containing word
+//	"a b"      phrase, words follow each other
+//	wor*       words starting with prefix
+//	-word      documents without word or phrase
+//	a OR b     either of terms
+//
+// Terms without OR between them must all match
+func ParseQuery(q string) (string, error) {
+	var (
+		result   strings.Builder
+		pendOr   bool
+		hasTerms bool
+	)
+
+	p := parser{input: []rune(q)}
+
+	for {
+		tok, ok, err := p.next()
+		if err != nil {
+			return "", err
+		}
+
+		if !ok {
+			break
+		}
+
+		if tok.or {
+			if !hasTerms || pendOr {
+				return "", errors.Wrap(ErrInvalidQuery, "OR must be placed between terms")
+			}
+
+			pendOr = true
+			continue
+		}
+
+		if len(tok.words) == 0 {
+			continue
+		}
+
+		if hasTerms {
+			if pendOr {
+				result.WriteString(" | ")
+			} else {
+				result.WriteString(" & ")
+			}
+		}
+
+		if tok.negate {
+			result.WriteString("!")
+		}
+
+		result.WriteString(tok.lexeme())
+
+		hasTerms = true
+		pendOr = false
+	}
+
+	if pendOr {
+		return "", errors.Wrap(ErrInvalidQuery, "OR must be placed between terms")
+	}
+
+	if !hasTerms {
+		return "", errors.Wrap(ErrInvalidQuery, "query has no words")
+	}
+
+	return result.String(), nil
+}
+
+type token struct {
+	words  []string
+	negate bool
+	prefix bool
+	or     bool
+}
+
+// Formats term, several words are joined as phrase
+func (t token) lexeme() string {
+	term := strings.Join(t.words, " <-> ")
+
+	if t.prefix {
+		term += ":*"
+	}
+
+	if len(t.words) > 1 {
+		term = "(" + term + ")"
+	}
+
+	return term
+}
+
+type parser struct {
+	input []rune
+	pos   int
+}
+
+func (p *parser) next() (token, bool, error) {
+	var tok token
+
+	// Skip spaces
+	for p.pos < len(p.input) && unicode.IsSpace(p.input[p.pos]) {
+		p.pos++
+	}
+
+	if p.pos >= len(p.input) {
+		return tok, false, nil
+	}
+
+	if p.input[p.pos] == '-' {
+		tok.negate = true
+		p.pos++
+	}
+
+	// Phrase
+	if p.pos < len(p.input) && p.input[p.pos] == '"' {
+		end := p.pos + 1
+		for end < len(p.input) && p.input[end] != '"' {
+			end++
+		}
+
+		if end >= len(p.input) {
+			return tok, false, errors.Wrap(ErrInvalidQuery, "unterminated phrase")
+		}
+
+		tok.words = splitWords(string(p.input[p.pos+1 : end]))
+		p.pos = end + 1
+
+		return tok, true, nil
+	}
+
+	// Word
+	start := p.pos
+	for p.pos < len(p.input) && !unicode.IsSpace(p.input[p.pos]) && p.input[p.pos] != '"' {
+		p.pos++
+	}
+
+	word := string(p.input[start:p.pos])
+
+	if word == "OR" && !tok.negate {
+		tok.or = true
+		return tok, true, nil
+	}
+
+	word, tok.prefix = strings.CutSuffix(word, "*")
+	tok.words = splitWords(word)
+
+	return tok, true, nil
+}
+
+// Splits text into lowercase words dropping tsquery operators and punctuation
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,65 @@
+package search
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+		err   error
+	}{
+		{name: "single word", query: "love", want: "love"},
+		{name: "words are lowercased", query: "LoVe", want: "love"},
+		{name: "all words must match", query: "love  song", want: "love & song"},
+		{name: "punctuation is dropped", query: "love! & (song)", want: "love & song"},
+		{name: "non latin letters", query: "Привет мир", want: "привет & мир"},
+		{name: "phrase", query: `"love me do"`, want: "(love <-> me <-> do)"},
+		{name: "single word phrase", query: `"love"`, want: "love"},
+		{name: "phrase next to word", query: `yellow"sub marine"`, want: "yellow & (sub <-> marine)"},
+		{name: "hyphenated word is phrase", query: "rock-n-roll", want: "(rock <-> n <-> roll)"},
+		{name: "prefix", query: "lov*", want: "lov:*"},
+		{name: "prefix after other word", query: "love so*", want: "love & so:*"},
+		{name: "negated word", query: "love -hate", want: "love & !hate"},
+		{name: "negated phrase", query: `love -"bad day"`, want: "love & !(bad <-> day)"},
+		{name: "negated prefix", query: "-lov*", want: "!lov:*"},
+		{name: "or", query: "rock OR pop", want: "rock | pop"},
+		{name: "or with phrase", query: `"let it be" OR yesterday`, want: "(let <-> it <-> be) | yesterday"},
+		{name: "or binds adjacent terms", query: "rock OR pop song", want: "rock | pop & song"},
+		{name: "lowercase or is word", query: "rock or pop", want: "rock & or & pop"},
+		{name: "negated or is word", query: "rock -OR", want: "rock & !or"},
+		{name: "empty query", query: "", err: ErrInvalidQuery},
+		{name: "only spaces", query: "   ", err: ErrInvalidQuery},
+		{name: "only operators", query: "& | !", err: ErrInvalidQuery},
+		{name: "empty phrase", query: `""`, err: ErrInvalidQuery},
+		{name: "unterminated phrase", query: `love "me do`, err: ErrInvalidQuery},
+		{name: "leading or", query: "OR rock", err: ErrInvalidQuery},
+		{name: "trailing or", query: "rock OR", err: ErrInvalidQuery},
+		{name: "double or", query: "rock OR OR pop", err: ErrInvalidQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuery(tt.query)
+
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("ParseQuery(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Sadere/song-depository/internal/config"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/Sadere/song-depository/internal/repository"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type IAlbumService interface {
+	List(ctx context.Context, artistID *uint64, cursor string, limit uint) (*domain.AlbumPage, error)
+	Get(ctx context.Context, albumID uint64) (*model.Album, error)
+	Add(ctx context.Context, album *model.Album) error
+	Modify(ctx context.Context, album *model.Album) error
+	Remove(ctx context.Context, albumID uint64) error
+	SetTracks(ctx context.Context, albumID uint64, tracks []domain.TrackRequest) (*model.Album, error)
+}
+
+type AlbumService struct {
+	config    *config.Config
+	albumRepo repository.AlbumRepository
+	log       *zap.SugaredLogger
+}
+
+func NewAlbumService(
+	config *config.Config,
+	albumRepo repository.AlbumRepository,
+	log *zap.SugaredLogger,
+) *AlbumService {
+	return &AlbumService{
+		config:    config,
+		albumRepo: albumRepo,
+		log:       log,
+	}
+}
+
+func (s *AlbumService) List(ctx context.Context, artistID *uint64, cursor string, limit uint) (*domain.AlbumPage, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	if limit == 0 {
+		limit = s.config.PageSize
+	}
+
+	if limit > s.config.MaxPageSize {
+		return nil, errors.Wrapf(domain.ErrPageLimit, "%d > %d", limit, s.config.MaxPageSize)
+	}
+
+	return s.albumRepo.List(ctx, artistID, cursor, limit)
+}
+
+func (s *AlbumService) Get(ctx context.Context, albumID uint64) (*model.Album, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	return s.albumRepo.GetById(ctx, albumID)
+}
+
+// Saves new album without tracks
+func (s *AlbumService) Add(ctx context.Context, album *model.Album) error {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	if len(album.Type) == 0 {
+		album.Type = model.AlbumLP
+	}
+
+	return s.albumRepo.Create(ctx, album)
+}
+
+// Replaces album fields, track listing is kept
+func (s *AlbumService) Modify(ctx context.Context, album *model.Album) error {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	if len(album.Type) == 0 {
+		album.Type = model.AlbumLP
+	}
+
+	return s.albumRepo.Update(ctx, album)
+}
+
+// Removes album, its songs are kept
+func (s *AlbumService) Remove(ctx context.Context, albumID uint64) error {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.albumRepo.Delete(ctx, albumID)
+}
+
+// Replaces track listing of album, tracks are numbered in given order on each disc
+func (s *AlbumService) SetTracks(ctx context.Context, albumID uint64, requests []domain.TrackRequest) (*model.Album, error) {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	tracks := make([]model.Track, 0, len(requests))
+	listed := make(map[uint64]bool, len(requests))
+	discTracks := make(map[uint]uint)
+
+	for _, req := range requests {
+		if listed[req.SongID] {
+			return nil, errors.Wrapf(domain.ErrDuplicateTrack, "song id %d", req.SongID)
+		}
+
+		listed[req.SongID] = true
+
+		disc := req.DiscNumber
+		if disc == 0 {
+			disc = 1
+		}
+
+		discTracks[disc]++
+
+		tracks = append(tracks, model.Track{
+			SongID:      req.SongID,
+			DiscNumber:  disc,
+			TrackNumber: discTracks[disc],
+		})
+	}
+
+	err := s.albumRepo.SetTracks(ctx, albumID, tracks)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.albumRepo.GetById(ctx, albumID)
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Sadere/song-depository/internal/config"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/Sadere/song-depository/internal/repository"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+type IArtistService interface {
+	List(ctx context.Context, cursor string, limit uint) (*domain.ArtistPage, error)
+	Get(ctx context.Context, artistID uint64) (*model.Artist, error)
+	GetBySlug(ctx context.Context, slug string) (*model.Artist, error)
+	Rename(ctx context.Context, artistID uint64, name string) (*model.Artist, error)
+	MergeAliases(ctx context.Context, artistID uint64, aliases []string) (*model.Artist, error)
+	Remove(ctx context.Context, artistID uint64) error
+}
+
+type ArtistService struct {
+	config     *config.Config
+	artistRepo repository.ArtistRepository
+	log        *zap.SugaredLogger
+}
+
+func NewArtistService(
+	config *config.Config,
+	artistRepo repository.ArtistRepository,
+	log *zap.SugaredLogger,
+) *ArtistService {
+	return &ArtistService{
+		config:     config,
+		artistRepo: artistRepo,
+		log:        log,
+	}
+}
+
+func (s *ArtistService) List(ctx context.Context, cursor string, limit uint) (*domain.ArtistPage, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	if limit == 0 {
+		limit = s.config.PageSize
+	}
+
+	if limit > s.config.MaxPageSize {
+		return nil, errors.Wrapf(domain.ErrPageLimit, "%d > %d", limit, s.config.MaxPageSize)
+	}
+
+	return s.artistRepo.List(ctx, cursor, limit)
+}
+
+func (s *ArtistService) Get(ctx context.Context, artistID uint64) (*model.Artist, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	return s.artistRepo.GetById(ctx, artistID)
+}
+
+func (s *ArtistService) GetBySlug(ctx context.Context, slug string) (*model.Artist, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	return s.artistRepo.GetBySlug(ctx, slug)
+}
+
+// Changes artist name, songs of artist are renamed too
+func (s *ArtistService) Rename(ctx context.Context, artistID uint64, name string) (*model.Artist, error) {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.artistRepo.Rename(ctx, artistID, name)
+}
+
+// Adds aliases to artist, artists known by them are merged into it
+func (s *ArtistService) MergeAliases(ctx context.Context, artistID uint64, aliases []string) (*model.Artist, error) {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.artistRepo.MergeAliases(ctx, artistID, aliases)
+}
+
+// Removes artist, only artists without songs and albums can be removed
+func (s *ArtistService) Remove(ctx context.Context, artistID uint64) error {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.artistRepo.Delete(ctx, artistID)
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Sadere/song-depository/internal/chordpro"
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/pkg/errors"
+)
+
+// Largest transposition in semitones, larger ones are rejected since shifting by octave keeps chord names
+const maxTranspose = 11
+
+// Validates ChordPro document and stores it as chord sheet of song
+func (s *SongService) ImportChordPro(ctx context.Context, songID uint64, source string) (*model.ChordSheet, error) {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	sheet, err := chordpro.Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sheet.Sections) == 0 {
+		return nil, errors.Wrap(domain.ErrInvalidChordPro, "document has no lyrics")
+	}
+
+	err = s.songRepo.SetChordSheet(ctx, songID, source)
+	if err != nil {
+		return nil, errors.Wrap(err, "songRepo.SetChordSheet")
+	}
+
+	return sheet, nil
+}
+
+// Renders chord sheet of song transposed by requested semitones, title defaults to song name
+func (s *SongService) ChordSheet(ctx context.Context, songID uint64, query domain.ChordSheetQuery) (*domain.RenderedChordSheet, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	switch query.Format {
+	case "":
+		query.Format = domain.ChordFormatText
+	case domain.ChordFormatText, domain.ChordFormatJSON:
+	default:
+		return nil, domain.ErrChordFormat
+	}
+
+	if query.Accidentals != "" && query.Accidentals != chordpro.Sharp && query.Accidentals != chordpro.Flat {
+		return nil, domain.ErrAccidentals
+	}
+
+	if query.Transpose < -maxTranspose || query.Transpose > maxTranspose {
+		return nil, domain.ErrTranspose
+	}
+
+	source, err := s.songRepo.GetChordSheet(ctx, songID)
+	if err != nil {
+		return nil, errors.Wrap(err, "songRepo.GetChordSheet")
+	}
+
+	sheet, err := chordpro.Parse(source)
+	if err != nil {
+		return nil, errors.Wrap(err, "chordpro.Parse")
+	}
+
+	if len(sheet.Title) == 0 {
+		song, err := s.songRepo.GetById(ctx, songID, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, "songRepo.GetById")
+		}
+
+		sheet.Title = song.Name
+	}
+
+	if query.Transpose != 0 || query.Accidentals != "" {
+		chordpro.Transpose(sheet, query.Transpose, query.Accidentals)
+	}
+
+	if query.Format == domain.ChordFormatJSON {
+		return &domain.RenderedChordSheet{Sheet: sheet}, nil
+	}
+
+	return &domain.RenderedChordSheet{Text: chordpro.RenderText(sheet)}, nil
+}
+
+// Removes chord sheet of song
+func (s *SongService) RemoveChordSheet(ctx context.Context, songID uint64) error {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.songRepo.DeleteChordSheet(ctx, songID)
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/pkg/errors"
+)
+
+// Reports pairs of songs which are likely the same one, threshold is minimal similarity of their names
+func (s *SongService) Duplicates(ctx context.Context, threshold *float64, limit uint) (*domain.DuplicateReport, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	report := &domain.DuplicateReport{
+		Threshold: s.config.DuplicateThreshold,
+		Limit:     limit,
+	}
+
+	if threshold != nil {
+		report.Threshold = *threshold
+	}
+
+	if report.Threshold < 0 || report.Threshold > 1 {
+		return nil, errors.Wrapf(domain.ErrThreshold, "%v", report.Threshold)
+	}
+
+	if report.Limit == 0 {
+		report.Limit = s.config.PageSize
+	}
+
+	if report.Limit > s.config.MaxPageSize {
+		return nil, errors.Wrapf(domain.ErrPageLimit, "%d > %d", report.Limit, s.config.MaxPageSize)
+	}
+
+	candidates, err := s.songRepo.FindDuplicates(ctx, report.Threshold, report.Limit)
+	if err != nil {
+		return nil, errors.Wrap(err, "songRepo.FindDuplicates")
+	}
+
+	report.Candidates = candidates
+
+	return report, nil
+}
+
+// Merges duplicate into song, duplicate ID redirects to song afterwards.
+// Non-zero version must match current song version
+func (s *SongService) Merge(
+	ctx context.Context,
+	songID, duplicateID, version uint64,
+	note domain.ChangeNote,
+) (*model.Song, error) {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	note = changeNote(note, fmt.Sprintf("merged song %d", duplicateID))
+
+	return s.songRepo.Merge(ctx, songID, duplicateID, version, note)
+}
+
+// Returns ID of song merged song was merged into
+func (s *SongService) MergedInto(ctx context.Context, songID uint64) (uint64, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	return s.songRepo.RedirectTarget(ctx, songID)
+}
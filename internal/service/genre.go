@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+
+	"github.com/Sadere/song-depository/internal/config"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/Sadere/song-depository/internal/repository"
+	"go.uber.org/zap"
+)
+
+type IGenreService interface {
+	List(ctx context.Context) (model.Genres, error)
+	Add(ctx context.Context, name string) (*model.Genre, error)
+	Remove(ctx context.Context, genreID uint64) error
+}
+
+type GenreService struct {
+	config    *config.Config
+	genreRepo repository.GenreRepository
+	log       *zap.SugaredLogger
+}
+
+func NewGenreService(
+	config *config.Config,
+	genreRepo repository.GenreRepository,
+	log *zap.SugaredLogger,
+) *GenreService {
+	return &GenreService{
+		config:    config,
+		genreRepo: genreRepo,
+		log:       log,
+	}
+}
+
+func (s *GenreService) List(ctx context.Context) (model.Genres, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	return s.genreRepo.List(ctx)
+}
+
+// Adds genre to vocabulary, its slug is made of name
+func (s *GenreService) Add(ctx context.Context, name string) (*model.Genre, error) {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.genreRepo.Create(ctx, name)
+}
+
+// Removes genre, only genres not set on songs can be removed
+func (s *GenreService) Remove(ctx context.Context, genreID uint64) error {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.genreRepo.Delete(ctx, genreID)
+}
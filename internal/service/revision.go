@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"time"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/lyrics"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/pkg/errors"
+)
+
+// Lists revisions of song, newest first
+func (s *SongService) Revisions(ctx context.Context, songID uint64, cursor string, limit uint) (*domain.RevisionPage, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	if limit == 0 {
+		limit = s.config.PageSize
+	}
+
+	if limit > s.config.MaxPageSize {
+		return nil, errors.Wrapf(domain.ErrPageLimit, "%d > %d", limit, s.config.MaxPageSize)
+	}
+
+	return s.songRepo.ListRevisions(ctx, songID, cursor, limit)
+}
+
+// Fetches revision of song with its fields
+func (s *SongService) Revision(ctx context.Context, songID, revision uint64) (*model.SongRevision, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	return s.songRepo.GetRevision(ctx, songID, revision)
+}
+
+// Compares revision of song with an earlier one, by default with previous revision.
+// First revision is compared with empty song
+func (s *SongService) DiffRevisions(ctx context.Context, songID, revision uint64, against *uint64) (*domain.RevisionDiff, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	to, err := s.songRepo.GetRevision(ctx, songID, revision)
+	if err != nil {
+		return nil, errors.Wrap(err, "songRepo.GetRevision")
+	}
+
+	diff := &domain.RevisionDiff{
+		From: revision - 1,
+		To:   revision,
+	}
+
+	if against != nil {
+		diff.From = *against
+	}
+
+	from := &model.SongSnapshot{}
+
+	if diff.From > 0 {
+		rev, err := s.songRepo.GetRevision(ctx, songID, diff.From)
+		if err != nil {
+			return nil, errors.Wrap(err, "songRepo.GetRevision")
+		}
+
+		from = rev.Snapshot
+	}
+
+	diff.Changes = snapshotChanges(from, to.Snapshot)
+	diff.Diff = lyrics.UnifiedDiff(
+		from.Text,
+		to.Snapshot.Text,
+		fmt.Sprintf("revision %d", diff.From),
+		fmt.Sprintf("revision %d", diff.To),
+	)
+
+	return diff, nil
+}
+
+// Sets song fields to ones saved by revision, it's saved as new revision.
+// Restored provider fields are marked as set by hand. Non-zero version must match current song version
+func (s *SongService) RestoreRevision(
+	ctx context.Context,
+	songID, revision, version uint64,
+	note domain.ChangeNote,
+) (*model.Song, error) {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	rev, err := s.songRepo.GetRevision(ctx, songID, revision)
+	if err != nil {
+		return nil, errors.Wrap(err, "songRepo.GetRevision")
+	}
+
+	snapshot := rev.Snapshot
+
+	var releaseDate *time.Time
+
+	if snapshot.ReleaseDate != nil {
+		date, err := time.Parse(time.DateOnly, *snapshot.ReleaseDate)
+		if err != nil {
+			return nil, errors.Wrap(err, "time.Parse")
+		}
+
+		releaseDate = &date
+	}
+
+	note = changeNote(note, fmt.Sprintf("restored revision %d", revision))
+
+	return s.songRepo.Replace(ctx, songID, version, note, func(song *model.Song) error {
+		restored := *song
+
+		restored.Name = snapshot.Name
+		restored.Group = snapshot.Group
+		restored.Text = snapshot.Text
+		restored.ReleaseDate = releaseDate
+		restored.Link = snapshot.Link
+		restored.Credits = snapshot.Credits
+		restored.Genres = snapshot.Genres
+		restored.Tags = snapshot.Tags
+		restored.ManualFields = song.ManualFields.With(changedFields(song, &restored)...)
+
+		*song = restored
+
+		return nil
+	})
+}
+
+// Returns changes of song fields other than lyrics
+func snapshotChanges(from, to *model.SongSnapshot) []domain.FieldChange {
+	changes := make([]domain.FieldChange, 0)
+
+	compare := func(field string, oldValue, newValue any) {
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes = append(changes, domain.FieldChange{
+				Field: field,
+				Old:   oldValue,
+				New:   newValue,
+			})
+		}
+	}
+
+	compare(model.FieldName, from.Name, to.Name)
+	compare(model.FieldGroup, from.Group, to.Group)
+	compare(model.FieldReleaseDate, from.ReleaseDate, to.ReleaseDate)
+	compare(model.FieldLink, from.Link, to.Link)
+	compare(model.FieldCredits, creditNames(from.Credits), creditNames(to.Credits))
+	compare(model.FieldGenres, labelList(from.Genres), labelList(to.Genres))
+	compare(model.FieldTags, labelList(from.Tags), labelList(to.Tags))
+
+	return changes
+}
+
+// Formats credits as artist names with roles, artist IDs don't matter for comparison
+func creditNames(credits model.Credits) []string {
+	names := make([]string, len(credits))
+
+	for i, credit := range credits {
+		names[i] = fmt.Sprintf("%s (%s)", credit.Artist, credit.Role)
+	}
+
+	return names
+}
+
+// Returns labels as non-nil sorted slice
+func labelList(labels model.Labels) []string {
+	list := slices.Clone([]string(labels))
+	if list == nil {
+		list = make([]string, 0)
+	}
+
+	slices.Sort(list)
+
+	return list
+}
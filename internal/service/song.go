@@ -1,134 +1,740 @@
 package service
 
 import (
+	"bytes"
 	"context"
-	"database/sql"
-	"fmt"
-	"net/http"
-	"net/url"
+	"encoding/json"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/Sadere/song-depository/internal/config"
 	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/lyrics"
+	"github.com/Sadere/song-depository/internal/metadata"
 	"github.com/Sadere/song-depository/internal/model"
+	"github.com/Sadere/song-depository/internal/patch"
 	"github.com/Sadere/song-depository/internal/repository"
-	"github.com/go-resty/resty/v2"
+	"github.com/go-playground/validator/v10"
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 )
 
 type ISongService interface {
-	Add(song *model.Song) error
-	List(filter domain.SongFilter, page uint) (model.Songs, error)
-	Song(songID uint64, verse int) (string, error)
-	Modify(songID uint64, req domain.UpdateSongRequest) error
-	Remove(songID uint64) error
+	Add(ctx context.Context, song *model.Song, note domain.ChangeNote) error
+	EnrichmentState(ctx context.Context, songID uint64) (*model.EnrichmentState, error)
+	Get(ctx context.Context, songID uint64, fields []string) (*model.Song, error)
+	List(ctx context.Context, filter domain.SongFilter, opts domain.ListOptions) (*domain.SongPage, error)
+	Facets(ctx context.Context, filter domain.SongFilter) (*domain.SongFacets, error)
+	Song(ctx context.Context, songID uint64, query domain.SectionQuery) (*domain.SongTextResponse, error)
+	Lyrics(ctx context.Context, songID uint64, query domain.LyricsQuery) (*domain.LyricsPage, error)
+	TimedLyrics(ctx context.Context, songID uint64) (model.TimedLines, error)
+	ImportLRC(ctx context.Context, songID uint64, lrc string) (model.TimedLines, error)
+	ExportLRC(ctx context.Context, songID uint64, enhanced bool) (string, error)
+	RemoveTimedLyrics(ctx context.Context, songID uint64) error
+	ActiveLines(ctx context.Context, songID uint64, position int64) (*domain.ActiveLyrics, error)
+	ImportChordPro(ctx context.Context, songID uint64, source string) (*model.ChordSheet, error)
+	ChordSheet(ctx context.Context, songID uint64, query domain.ChordSheetQuery) (*domain.RenderedChordSheet, error)
+	RemoveChordSheet(ctx context.Context, songID uint64) error
+	Revisions(ctx context.Context, songID uint64, cursor string, limit uint) (*domain.RevisionPage, error)
+	Revision(ctx context.Context, songID, revision uint64) (*model.SongRevision, error)
+	DiffRevisions(ctx context.Context, songID, revision uint64, against *uint64) (*domain.RevisionDiff, error)
+	RestoreRevision(ctx context.Context, songID, revision, version uint64, note domain.ChangeNote) (*model.Song, error)
+	Modify(ctx context.Context, songID, version uint64, req domain.UpdateSongRequest, note domain.ChangeNote) (uint64, error)
+	Patch(ctx context.Context, songID, version uint64, contentType string, patchDoc []byte, note domain.ChangeNote) (*model.Song, error)
+	Remove(ctx context.Context, songID, version uint64) error
+	Trash(ctx context.Context, opts domain.ListOptions) (*domain.SongPage, error)
+	Restore(ctx context.Context, songID uint64) (*model.Song, error)
+	HardDelete(ctx context.Context, songID uint64) error
+	Duplicates(ctx context.Context, threshold *float64, limit uint) (*domain.DuplicateReport, error)
+	Merge(ctx context.Context, songID, duplicateID, version uint64, note domain.ChangeNote) (*model.Song, error)
+	MergedInto(ctx context.Context, songID uint64) (uint64, error)
+	Refresh(ctx context.Context, songID uint64, req domain.RefreshSongRequest, note domain.ChangeNote) (*domain.RefreshResult, error)
+	RefreshFiltered(ctx context.Context, req domain.RefreshSongsRequest, note domain.ChangeNote) ([]domain.RefreshResult, error)
 }
 
 type SongService struct {
-	config   *config.Config
-	songRepo repository.SongRepository
-	log      *zap.SugaredLogger
+	config     *config.Config
+	songRepo   repository.SongRepository
+	enrichRepo repository.EnrichmentRepository
+	albumRepo  repository.AlbumRepository
+	provider   metadata.MetadataProvider
+	log        *zap.SugaredLogger
 }
 
-func NewSongService(config *config.Config, songRepo repository.SongRepository, log *zap.SugaredLogger) *SongService {
+func NewSongService(
+	config *config.Config,
+	songRepo repository.SongRepository,
+	enrichRepo repository.EnrichmentRepository,
+	albumRepo repository.AlbumRepository,
+	provider metadata.MetadataProvider,
+	log *zap.SugaredLogger,
+) *SongService {
 	return &SongService{
-		config:   config,
-		songRepo: songRepo,
-		log:      log,
+		config:     config,
+		songRepo:   songRepo,
+		enrichRepo: enrichRepo,
+		albumRepo:  albumRepo,
+		provider:   provider,
+		log:        log,
 	}
 }
 
-func (s *SongService) Add(song *model.Song) error {
-	// Request music info endpoint
-	var songDetail domain.SongDetail
+// Fills in missing author and reason of song change
+func changeNote(note domain.ChangeNote, reason string) domain.ChangeNote {
+	if len(note.Author) == 0 {
+		note.Author = domain.AnonymousAuthor
+	}
 
-	params := url.Values{
-		"group": {song.Group},
-		"song":  {song.Name},
+	if len(note.Reason) == 0 {
+		note.Reason = reason
 	}
 
-	infoEndPoint := fmt.Sprintf("%s/info?%s", s.config.MusicInfoAddress, params.Encode())
+	return note
+}
+
+// Limits ctx with DB query deadline
+func queryContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, timeout)
+}
 
-	s.log.Debug("music info endpoint request: ", infoEndPoint)
+// Saves new song, its details are looked up by enrichment workers
+func (s *SongService) Add(ctx context.Context, song *model.Song, note domain.ChangeNote) error {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
 
-	response, err := resty.New().R().
-		SetResult(&songDetail).
-		Get(infoEndPoint)
+	song.EnrichmentStatus = model.EnrichmentPending
 
+	err := s.songRepo.Create(ctx, song, changeNote(note, "song added"))
 	if err != nil {
-		return errors.Wrap(err, "songDetail")
+		return errors.Wrap(err, "songRepo.Create")
+	}
+
+	return nil
+}
+
+func (s *SongService) EnrichmentState(ctx context.Context, songID uint64) (*model.EnrichmentState, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	return s.enrichRepo.GetState(ctx, songID)
+}
+
+// Fetches song with requested fields, all fields are fetched when none requested
+func (s *SongService) Get(ctx context.Context, songID uint64, fields []string) (*model.Song, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	return s.songRepo.GetById(ctx, songID, model.FieldSet{}.With(fields...))
+}
+
+func (s *SongService) List(ctx context.Context, filter domain.SongFilter, opts domain.ListOptions) (*domain.SongPage, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	s.filterDefaults(&filter)
+
+	if opts.Limit == 0 {
+		opts.Limit = s.config.PageSize
 	}
 
-	s.log.Debug("music info endpoint response: ", response, " body: ", songDetail)
+	if opts.Limit > s.config.MaxPageSize {
+		return nil, errors.Wrapf(domain.ErrPageLimit, "%d > %d", opts.Limit, s.config.MaxPageSize)
+	}
 
-	if response.StatusCode() != http.StatusOK {
-		return domain.ErrSongDetail
+	if len(opts.Fields) == 0 {
+		opts.Fields = model.DefaultListFields
 	}
 
-	// Process song detail response
-	releaseDate, err := time.Parse("02.01.2006", songDetail.ReleaseDate)
+	page, err := s.songRepo.ListFiltered(ctx, filter, opts)
 	if err != nil {
-		return errors.Wrap(err, "time.Parse")
+		return nil, err
+	}
+
+	page.Fields = opts.Fields
+
+	return page, nil
+}
+
+// Counts songs matching filter per genre, tag, artist and release year
+func (s *SongService) Facets(ctx context.Context, filter domain.SongFilter) (*domain.SongFacets, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	s.filterDefaults(&filter)
+
+	return s.songRepo.Facets(ctx, filter, s.config.FacetLimit)
+}
+
+// Sets search settings filter doesn't specify
+func (s *SongService) filterDefaults(filter *domain.SongFilter) {
+	if len(filter.SearchConfig) == 0 {
+		filter.SearchConfig = s.config.SearchConfig
+	}
+
+	if len(filter.Match) == 0 {
+		filter.Match = domain.MatchContains
+	}
+
+	if filter.FuzzyThreshold == nil {
+		threshold := s.config.FuzzyThreshold
+		filter.FuzzyThreshold = &threshold
+	}
+}
+
+// Returns lyrics sections selected by query: one by index, all of type or all with label.
+// First section is returned when query selects nothing
+func (s *SongService) Song(ctx context.Context, songID uint64, query domain.SectionQuery) (*domain.SongTextResponse, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	if len(query.Type) > 0 && !slices.Contains(model.SectionTypes, query.Type) {
+		return nil, errors.Wrapf(domain.ErrSectionType, "%q", query.Type)
 	}
-	song.ReleaseDate = releaseDate
-	song.Text = songDetail.Text
-	song.Link = songDetail.Link
 
-	// Save song to storage
-	err = s.songRepo.Create(context.TODO(), song)
+	sections, err := s.songRepo.GetSections(ctx, songID)
 	if err != nil {
-		return errors.Wrap(err, "songRepo.Create")
+		return nil, errors.Wrap(err, "songRepo.GetSections")
 	}
 
-	return nil
+	selected := make(model.Sections, 0)
+
+	switch {
+	case len(query.Type) > 0 || len(query.Label) > 0:
+		for _, section := range sections {
+			if len(query.Type) > 0 && section.Type != query.Type {
+				continue
+			}
+
+			if len(query.Label) > 0 && !strings.EqualFold(section.Label, query.Label) {
+				continue
+			}
+
+			selected = append(selected, section)
+		}
+	default:
+		index := 0
+		if query.Index != nil {
+			index = *query.Index
+		}
+
+		if index >= 0 && index < len(sections) {
+			selected = append(selected, sections[index])
+		}
+	}
+
+	if len(selected) == 0 {
+		return nil, domain.ErrVerseNotFound
+	}
+
+	texts := make([]string, len(selected))
+	for i, section := range selected {
+		texts[i] = strings.Join(section.Lines, "\n")
+	}
+
+	return &domain.SongTextResponse{
+		Text:     strings.Join(texts, "\n\n"),
+		Sections: selected,
+	}, nil
+}
+
+// Returns verses of lyrics selected by range or page, or lines selected by references
+func (s *SongService) Lyrics(ctx context.Context, songID uint64, query domain.LyricsQuery) (*domain.LyricsPage, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	ranged := query.VerseFrom != nil || query.VerseTo != nil
+	paged := query.Page > 0
+
+	if (ranged && paged) || (len(query.Lines) > 0 && (ranged || paged)) {
+		return nil, domain.ErrLyricsQuery
+	}
+
+	if query.Limit == 0 {
+		query.Limit = s.config.PageSize
+	}
+
+	if query.Limit > s.config.MaxPageSize {
+		return nil, errors.Wrapf(domain.ErrPageLimit, "%d > %d", query.Limit, s.config.MaxPageSize)
+	}
+
+	sections, err := s.songRepo.GetSections(ctx, songID)
+	if err != nil {
+		return nil, errors.Wrap(err, "songRepo.GetSections")
+	}
+
+	page := &domain.LyricsPage{
+		VerseCount: len(sections),
+		Verses:     make(model.Sections, 0),
+	}
+
+	last := len(sections) - 1
+	verseRange := &domain.RangeError{First: 0, Last: last}
+
+	if len(query.Lines) > 0 {
+		page.Lines = make([]domain.LyricsLine, 0)
+
+		for _, ref := range query.Lines {
+			if ref.Verse < 0 || ref.Verse > last {
+				return nil, verseRange
+			}
+
+			lines := sections[ref.Verse].Lines
+
+			if ref.LineFrom < 0 || ref.LineTo >= len(lines) {
+				return nil, &domain.RangeError{Verse: &ref.Verse, First: 0, Last: len(lines) - 1}
+			}
+
+			for i := ref.LineFrom; i <= ref.LineTo; i++ {
+				page.Lines = append(page.Lines, domain.LyricsLine{
+					Verse: ref.Verse,
+					Line:  i,
+					Text:  lines[i],
+				})
+			}
+		}
+
+		return page, nil
+	}
+
+	var from, to int
+
+	switch {
+	case ranged:
+		if query.VerseFrom != nil {
+			from = *query.VerseFrom
+		}
+
+		to = min(from+int(query.Limit)-1, last)
+		if query.VerseTo != nil {
+			to = *query.VerseTo
+		}
+
+		if from > to && query.VerseFrom != nil && query.VerseTo != nil {
+			return nil, errors.Wrap(domain.ErrInvalidRange, "verse_from > verse_to")
+		}
+
+		if from < 0 || from > last || to < 0 || to > last {
+			return nil, verseRange
+		}
+
+		if uint(to-from+1) > s.config.MaxPageSize {
+			return nil, errors.Wrapf(domain.ErrPageLimit, "%d > %d", to-from+1, s.config.MaxPageSize)
+		}
+	default:
+		if query.Page == 0 {
+			query.Page = 1
+		}
+
+		from = int((query.Page - 1) * query.Limit)
+		to = min(from+int(query.Limit)-1, last)
+
+		// Lyrics without verses have empty first page
+		if query.Page > 1 && from > last {
+			return nil, verseRange
+		}
+	}
+
+	if from <= to {
+		page.Verses = sections[from : to+1]
+	}
+
+	page.HasMore = to < last
+
+	return page, nil
+}
+
+// Updates song fields, non-zero version must match current song version. Returns new song version
+func (s *SongService) Modify(
+	ctx context.Context,
+	songID, version uint64,
+	req domain.UpdateSongRequest,
+	note domain.ChangeNote,
+) (uint64, error) {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.songRepo.Update(ctx, songID, version, req, changeNote(note, "song updated"))
+}
+
+// Moves song to trash, non-zero version must match current song version
+func (s *SongService) Remove(ctx context.Context, songID, version uint64) error {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.songRepo.Delete(ctx, songID, version)
+}
+
+// Lists songs in trash
+func (s *SongService) Trash(ctx context.Context, opts domain.ListOptions) (*domain.SongPage, error) {
+	return s.List(ctx, domain.SongFilter{Deleted: true}, opts)
+}
+
+// Takes song out of trash
+func (s *SongService) Restore(ctx context.Context, songID uint64) (*model.Song, error) {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	err := s.songRepo.Restore(ctx, songID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.songRepo.GetById(ctx, songID, nil)
 }
 
-func (s *SongService) List(filter domain.SongFilter, page uint) (model.Songs, error) {
-	return s.songRepo.ListFiltered(context.TODO(), filter, page)
+// Removes song permanently, whether it's in trash or not
+func (s *SongService) HardDelete(ctx context.Context, songID uint64) error {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.songRepo.HardDelete(ctx, songID)
 }
 
-func (s *SongService) Song(songID uint64, verse int) (string, error) {
-	songText, err := s.songRepo.GetSongText(context.TODO(), songID)
+// Looks up song details again and saves changed fields
+func (s *SongService) Refresh(
+	ctx context.Context,
+	songID uint64,
+	req domain.RefreshSongRequest,
+	note domain.ChangeNote,
+) (*domain.RefreshResult, error) {
+	queryCtx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	song, err := s.songRepo.GetById(queryCtx, songID, nil)
 	if err != nil {
-		return "", errors.Wrap(err, "songRepo.GetSongText")
+		return nil, err
+	}
+
+	return s.refreshSong(ctx, song, req, changeNote(note, "metadata refresh"))
+}
+
+// Refreshes songs matching filter, at most RefreshBulkLimit songs are processed
+func (s *SongService) RefreshFiltered(
+	ctx context.Context,
+	req domain.RefreshSongsRequest,
+	note domain.ChangeNote,
+) ([]domain.RefreshResult, error) {
+	ctx, cancel := queryContext(ctx, s.config.BulkTimeout)
+	defer cancel()
+
+	note = changeNote(note, "metadata refresh")
+
+	// Lyrics are compared with found ones
+	opts := domain.ListOptions{
+		Fields: model.StoredFields,
 	}
 
-	// Get required verse
-	verses := strings.Split(songText, "\n\n")
+	results := make([]domain.RefreshResult, 0)
+
+	for uint(len(results)) < s.config.RefreshBulkLimit {
+		page, err := s.List(ctx, req.Filter, opts)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, song := range page.Songs {
+			if uint(len(results)) >= s.config.RefreshBulkLimit {
+				break
+			}
 
-	if verse > len(verses) {
-		return "", domain.ErrVerseNotFound
+			result, err := s.refreshSong(ctx, song, req.RefreshSongRequest, note)
+			if err != nil {
+				// Request is cancelled, no point to continue
+				if ctx.Err() != nil {
+					return nil, ctx.Err()
+				}
+
+				result = &domain.RefreshResult{
+					SongID:  song.ID,
+					Changes: []domain.FieldChange{},
+					Error:   err.Error(),
+				}
+			}
+
+			results = append(results, *result)
+		}
+
+		if page.NextCursor == nil {
+			break
+		}
+
+		opts.Cursor = *page.NextCursor
 	}
 
-	return verses[verse], nil
+	return results, nil
 }
 
-func (s *SongService) Modify(songID uint64, req domain.UpdateSongRequest) error {
-	// Check if song exists
-	_, err := s.songRepo.GetById(context.TODO(), songID)
-	if errors.Is(err, sql.ErrNoRows) {
-		return domain.ErrSongNotFound
+func (s *SongService) refreshSong(
+	ctx context.Context,
+	song *model.Song,
+	req domain.RefreshSongRequest,
+	note domain.ChangeNote,
+) (*domain.RefreshResult, error) {
+	detail, err := s.provider.Lookup(ctx, song.Group, song.Name)
+	if err != nil {
+		return nil, errors.Wrap(err, "provider.Lookup")
+	}
+
+	result := &domain.RefreshResult{
+		SongID:  song.ID,
+		Changes: make([]domain.FieldChange, 0),
+	}
+
+	var applied []string
+
+	// Compares field and applies new value unless field is protected
+	compare := func(field string, oldValue, newValue any, apply func()) {
+		if oldValue == newValue {
+			return
+		}
+
+		change := domain.FieldChange{
+			Field:     field,
+			Old:       oldValue,
+			New:       newValue,
+			Protected: song.ManualFields.Has(field) && !req.Force,
+		}
+
+		if !change.Protected {
+			apply()
+			applied = append(applied, field)
+		}
+
+		result.Changes = append(result.Changes, change)
+	}
+
+	text := lyrics.Normalize(detail.Text)
+
+	compare(model.FieldText, song.Text, text, func() {
+		song.Text = text
+	})
+
+	// Song without date takes it from albums when provider doesn't know it
+	releaseDate := detail.ReleaseDate
+	if releaseDate.IsZero() && song.ReleaseDate == nil {
+		albumDate, err := s.albumReleaseDate(ctx, song.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if albumDate != nil {
+			releaseDate = *albumDate
+		}
+	}
+
+	// Missing date isn't treated as change
+	if !releaseDate.IsZero() {
+		compare(model.FieldReleaseDate, formatDate(song.ReleaseDate), formatDate(&releaseDate), func() {
+			song.ReleaseDate = &releaseDate
+		})
+	}
+
+	compare(model.FieldLink, song.Link, detail.Link, func() {
+		song.Link = detail.Link
+	})
+
+	if req.DryRun || (len(applied) == 0 && song.EnrichmentStatus == model.EnrichmentEnriched) {
+		return result, nil
+	}
+
+	// Forced fields are taken from provider from now on
+	song.ManualFields = song.ManualFields.Without(applied...)
+	song.EnrichmentStatus = model.EnrichmentEnriched
+
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	err = s.songRepo.UpdateDetail(ctx, song, note)
+	if err != nil {
+		return nil, errors.Wrap(err, "songRepo.UpdateDetail")
 	}
 
+	result.Applied = true
+
+	return result, nil
+}
+
+func (s *SongService) albumReleaseDate(ctx context.Context, songID uint64) (*time.Time, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	date, err := s.albumRepo.SongReleaseDate(ctx, songID)
 	if err != nil {
-		return err
+		return nil, errors.Wrap(err, "albumRepo.SongReleaseDate")
 	}
 
-	return s.songRepo.Update(context.TODO(), songID, req)
+	return date, nil
 }
 
-func (s *SongService) Remove(songID uint64) error {
-	// Check if song exists
-	_, err := s.songRepo.GetById(context.TODO(), songID)
-	if errors.Is(err, sql.ErrNoRows) {
-		return domain.ErrSongNotFound
+// Formats date for comparison and output, nil for empty date
+func formatDate(date *time.Time) any {
+	if date == nil || date.IsZero() {
+		return nil
 	}
 
+	return date.Format(time.DateOnly)
+}
+
+// Song members which can't be changed by patch, optional ones must stay absent
+var readOnlyFields = []string{
+	"id", "created_at", "updated_at", "version", "artist_id", "enrichment_status", "manual_fields",
+	"deleted_at", "rank", "headline", "score", "text_length", "verse_count",
+}
+
+// Applies merge patch or JSON patch to song, non-zero version must match current song version.
+// Changed fields are marked as set by hand
+func (s *SongService) Patch(
+	ctx context.Context,
+	songID, version uint64,
+	contentType string,
+	patchDoc []byte,
+	note domain.ChangeNote,
+) (*model.Song, error) {
+	var apply func(doc, patch []byte) ([]byte, error)
+
+	switch contentType {
+	case domain.MergePatchContentType:
+		apply = patch.MergePatch
+	case domain.JSONPatchContentType:
+		apply = patch.JSONPatch
+	default:
+		return nil, domain.ErrPatchType
+	}
+
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.songRepo.Replace(ctx, songID, version, changeNote(note, "song patched"), func(song *model.Song) error {
+		original, err := json.Marshal(song)
+		if err != nil {
+			return err
+		}
+
+		patched, err := apply(original, patchDoc)
+		if err != nil {
+			return err
+		}
+
+		err = checkReadOnly(original, patched)
+		if err != nil {
+			return err
+		}
+
+		editable, err := editableMembers(patched)
+		if err != nil {
+			return err
+		}
+
+		// Unknown members are rejected
+		var fields domain.SongFields
+
+		decoder := json.NewDecoder(bytes.NewReader(editable))
+		decoder.DisallowUnknownFields()
+
+		err = decoder.Decode(&fields)
+		if err != nil {
+			return errors.Wrap(domain.ErrInvalidSong, err.Error())
+		}
+
+		err = validator.New().Struct(fields)
+		if err != nil {
+			return errors.Wrap(domain.ErrInvalidSong, err.Error())
+		}
+
+		updated := model.Song{
+			Name:        fields.Name,
+			Group:       fields.Group,
+			Text:        fields.Text,
+			ReleaseDate: fields.ReleaseDate,
+			Link:        fields.Link,
+			Credits:     fields.Credits,
+			Genres:      fields.Genres,
+			Tags:        fields.Tags,
+		}
+
+		changed := changedFields(song, &updated)
+
+		song.Name = updated.Name
+		song.Group = updated.Group
+		song.Text = updated.Text
+		song.ReleaseDate = updated.ReleaseDate
+		song.Link = updated.Link
+		song.Credits = updated.Credits
+		song.Genres = updated.Genres
+		song.Tags = updated.Tags
+		song.ManualFields = song.ManualFields.With(changed...)
+
+		return nil
+	})
+}
+
+// Checks that read-only fields are kept by patch
+func checkReadOnly(original, patched []byte) error {
+	var before, after map[string]any
+
+	err := json.Unmarshal(original, &before)
 	if err != nil {
 		return err
 	}
 
-	return s.songRepo.Delete(context.TODO(), songID)
+	err = json.Unmarshal(patched, &after)
+	if err != nil {
+		return errors.Wrap(domain.ErrInvalidSong, "song must be object")
+	}
+
+	for _, field := range readOnlyFields {
+		value, ok := after[field]
+		previous, had := before[field]
+
+		if ok != had || !reflect.DeepEqual(value, previous) {
+			return errors.Wrap(domain.ErrReadOnlyField, field)
+		}
+	}
+
+	return nil
+}
+
+// Returns patched song without read-only members, which are checked already
+func editableMembers(patched []byte) ([]byte, error) {
+	var members map[string]json.RawMessage
+
+	err := json.Unmarshal(patched, &members)
+	if err != nil {
+		return nil, errors.Wrap(domain.ErrInvalidSong, "song must be object")
+	}
+
+	for _, field := range readOnlyFields {
+		delete(members, field)
+	}
+
+	return json.Marshal(members)
+}
+
+// Returns names of editable fields which differ
+func changedFields(a, b *model.Song) []string {
+	var changed []string
+
+	if a.Name != b.Name {
+		changed = append(changed, model.FieldName)
+	}
+
+	if a.Group != b.Group {
+		changed = append(changed, model.FieldGroup)
+	}
+
+	if a.Text != b.Text {
+		changed = append(changed, model.FieldText)
+	}
+
+	if formatDate(a.ReleaseDate) != formatDate(b.ReleaseDate) {
+		changed = append(changed, model.FieldReleaseDate)
+	}
+
+	if a.Link != b.Link {
+		changed = append(changed, model.FieldLink)
+	}
+
+	return changed
 }
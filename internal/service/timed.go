@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Sadere/song-depository/internal/domain"
+	"github.com/Sadere/song-depository/internal/lyrics"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/pkg/errors"
+)
+
+// Returns synchronized lyrics of song
+func (s *SongService) TimedLyrics(ctx context.Context, songID uint64) (model.TimedLines, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	return s.songRepo.GetTimedLines(ctx, songID)
+}
+
+// Replaces synchronized lyrics of song with ones parsed from LRC
+func (s *SongService) ImportLRC(ctx context.Context, songID uint64, lrc string) (model.TimedLines, error) {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	lines, err := lyrics.ParseLRC(lrc)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		return nil, errors.Wrap(domain.ErrInvalidLRC, "no timed lines")
+	}
+
+	err = s.songRepo.SetTimedLines(ctx, songID, lines)
+	if err != nil {
+		return nil, errors.Wrap(err, "songRepo.SetTimedLines")
+	}
+
+	return lines, nil
+}
+
+// Formats synchronized lyrics of song as LRC, word timings are kept when enhanced
+func (s *SongService) ExportLRC(ctx context.Context, songID uint64, enhanced bool) (string, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	lines, err := s.songRepo.GetTimedLines(ctx, songID)
+	if err != nil {
+		return "", errors.Wrap(err, "songRepo.GetTimedLines")
+	}
+
+	if len(lines) == 0 {
+		return "", domain.ErrNoTimedLyrics
+	}
+
+	song, err := s.songRepo.GetById(ctx, songID, nil)
+	if err != nil {
+		return "", errors.Wrap(err, "songRepo.GetById")
+	}
+
+	return lyrics.FormatLRC(song.Name, song.Group, lines, enhanced), nil
+}
+
+// Removes synchronized lyrics of song
+func (s *SongService) RemoveTimedLyrics(ctx context.Context, songID uint64) error {
+	ctx, cancel := queryContext(ctx, s.config.WriteTimeout)
+	defer cancel()
+
+	return s.songRepo.SetTimedLines(ctx, songID, nil)
+}
+
+// Returns lines sung at playback position and line following them
+func (s *SongService) ActiveLines(ctx context.Context, songID uint64, position int64) (*domain.ActiveLyrics, error) {
+	ctx, cancel := queryContext(ctx, s.config.ReadTimeout)
+	defer cancel()
+
+	lines, err := s.songRepo.GetTimedLines(ctx, songID)
+	if err != nil {
+		return nil, errors.Wrap(err, "songRepo.GetTimedLines")
+	}
+
+	if len(lines) == 0 {
+		return nil, domain.ErrNoTimedLyrics
+	}
+
+	active := &domain.ActiveLyrics{
+		Position: position,
+		Lines:    make([]domain.ActiveLine, 0),
+	}
+
+	// Lines are ordered by start, first line starting after position follows active ones
+	next := sort.Search(len(lines), func(i int) bool {
+		return lines[i].Start > position
+	})
+
+	for i := next; i < len(lines); i++ {
+		if len(lines[i].Text) > 0 {
+			active.Next = lines[i]
+			break
+		}
+	}
+
+	if next == 0 {
+		return active, nil
+	}
+
+	// Blank lines mark breaks, nothing is sung during them
+	start := lines[next-1].Start
+
+	for i := next - 1; i >= 0 && lines[i].Start == start; i-- {
+		line := lines[i]
+
+		if len(line.Text) == 0 || (line.End != nil && position >= *line.End) {
+			continue
+		}
+
+		active.Lines = append(active.Lines, domain.ActiveLine{
+			Line: line,
+			Word: activeWord(line.Words, position),
+		})
+	}
+
+	// Keep lyrics order of lines sharing start
+	for i, j := 0, len(active.Lines)-1; i < j; i, j = i+1, j-1 {
+		active.Lines[i], active.Lines[j] = active.Lines[j], active.Lines[i]
+	}
+
+	return active, nil
+}
+
+// Returns index of last word started by position, nil before first word
+func activeWord(words model.TimedWords, position int64) *int {
+	i := sort.Search(len(words), func(i int) bool {
+		return words[i].Start > position
+	})
+
+	if i == 0 {
+		return nil
+	}
+
+	i--
+
+	return &i
+}
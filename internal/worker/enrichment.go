@@ -0,0 +1,161 @@
+// Background jobs running inside app process
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Sadere/song-depository/internal/config"
+	"github.com/Sadere/song-depository/internal/lyrics"
+	"github.com/Sadere/song-depository/internal/metadata"
+	"github.com/Sadere/song-depository/internal/model"
+	"github.com/Sadere/song-depository/internal/repository"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+)
+
+// Longest delay between attempts of enrichment job
+const maxRetryDelay = 24 * time.Hour
+
+// Pool of workers filling in details of pending songs
+type EnrichmentPool struct {
+	config   *config.Config
+	repo     repository.EnrichmentRepository
+	provider metadata.MetadataProvider
+	log      *zap.SugaredLogger
+	wg       sync.WaitGroup
+}
+
+func NewEnrichmentPool(
+	cfg *config.Config,
+	repo repository.EnrichmentRepository,
+	provider metadata.MetadataProvider,
+	log *zap.SugaredLogger,
+) *EnrichmentPool {
+	return &EnrichmentPool{
+		config:   cfg,
+		repo:     repo,
+		provider: provider,
+		log:      log,
+	}
+}
+
+// Starts workers, they run until ctx is cancelled
+func (p *EnrichmentPool) Start(ctx context.Context) {
+	interval := p.config.EnrichmentPollInterval
+
+	// Ticker can't run with non-positive interval
+	if interval <= 0 {
+		p.log.Warn("invalid enrichment poll interval: ", interval, " using: ", config.DefaultEnrichmentPollInterval)
+
+		interval = config.DefaultEnrichmentPollInterval
+	}
+
+	for i := uint(0); i < p.config.EnrichmentWorkers; i++ {
+		p.wg.Add(1)
+
+		go func() {
+			defer p.wg.Done()
+
+			p.run(ctx, interval)
+		}()
+	}
+}
+
+// Waits for workers to stop
+func (p *EnrichmentPool) Wait() {
+	p.wg.Wait()
+}
+
+func (p *EnrichmentPool) run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Process due jobs until queue is drained
+		for ctx.Err() == nil {
+			processed, err := p.repo.ProcessNext(ctx, p.enrich)
+			if err != nil {
+				if ctx.Err() == nil {
+					p.log.Error("failed to process enrichment job: ", err)
+				}
+
+				break
+			}
+
+			if !processed {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Looks up song details, failed lookups are retried with exponential delay
+func (p *EnrichmentPool) enrich(ctx context.Context, job *model.EnrichmentJob) error {
+	song := &job.Song
+
+	detail, err := p.provider.Lookup(ctx, song.Group, song.Name)
+
+	// Interrupted by shutdown, job stays in queue
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	if err == nil {
+		song.Text = lyrics.Normalize(detail.Text)
+		song.Link = detail.Link
+		song.EnrichmentStatus = model.EnrichmentEnriched
+
+		// Missing date is filled in from albums
+		if !detail.ReleaseDate.IsZero() {
+			song.ReleaseDate = &detail.ReleaseDate
+		}
+
+		p.log.Debug("song enriched, id: ", song.ID)
+
+		return nil
+	}
+
+	job.Attempts++
+	job.LastError = err.Error()
+
+	// Provider doesn't know the song, refuses request or attempts are exhausted
+	if errors.Is(err, metadata.ErrNotFound) || errors.Is(err, metadata.ErrRejected) ||
+		job.Attempts >= p.config.EnrichmentMaxAttempts {
+		song.EnrichmentStatus = model.EnrichmentFailed
+
+		p.log.Warn("song enrichment failed, id: ", song.ID, " attempts: ", job.Attempts, " error: ", err)
+
+		return nil
+	}
+
+	job.RetryIn = retryDelay(p.config.EnrichmentRetryDelay, job.Attempts)
+
+	// Don't retry until provider is back
+	var openErr *metadata.CircuitOpenError
+	if errors.As(err, &openErr) && openErr.RetryAfter > job.RetryIn {
+		job.RetryIn = openErr.RetryAfter
+	}
+
+	p.log.Info("song enrichment rescheduled, id: ", song.ID, " retry in: ", job.RetryIn, " error: ", err)
+
+	return nil
+}
+
+// Doubles base delay with each failed attempt up to max delay
+func retryDelay(base time.Duration, attempts uint) time.Duration {
+	delay := base
+
+	for i := uint(1); i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, maxRetryDelay)
+}
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		base     time.Duration
+		attempts uint
+		want     time.Duration
+	}{
+		{base: 30 * time.Second, attempts: 1, want: 30 * time.Second},
+		{base: 30 * time.Second, attempts: 3, want: 2 * time.Minute},
+		{base: 30 * time.Second, attempts: 20, want: maxRetryDelay},
+		{base: 30 * time.Second, attempts: 100, want: maxRetryDelay},
+		{base: 48 * time.Hour, attempts: 1, want: maxRetryDelay},
+		{base: time.Second, attempts: ^uint(0), want: maxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.base, tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%v, %d) = %v, want %v", tt.base, tt.attempts, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Sadere/song-depository/internal/config"
+	"github.com/Sadere/song-depository/internal/repository"
+	"go.uber.org/zap"
+)
+
+// Permanently removes songs kept in trash longer than retention period
+type TrashPurger struct {
+	config *config.Config
+	repo   repository.SongRepository
+	log    *zap.SugaredLogger
+	wg     sync.WaitGroup
+}
+
+func NewTrashPurger(
+	cfg *config.Config,
+	repo repository.SongRepository,
+	log *zap.SugaredLogger,
+) *TrashPurger {
+	return &TrashPurger{
+		config: cfg,
+		repo:   repo,
+		log:    log,
+	}
+}
+
+// Starts purger, it runs until ctx is cancelled. Zero retention disables purging
+func (p *TrashPurger) Start(ctx context.Context) {
+	if p.config.TrashRetention <= 0 || p.config.TrashPurgeInterval <= 0 {
+		return
+	}
+
+	p.wg.Add(1)
+
+	go func() {
+		defer p.wg.Done()
+
+		p.run(ctx)
+	}()
+}
+
+// Waits for purger to stop
+func (p *TrashPurger) Wait() {
+	p.wg.Wait()
+}
+
+func (p *TrashPurger) run(ctx context.Context) {
+	ticker := time.NewTicker(p.config.TrashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Removes expired songs in batches until none are left
+func (p *TrashPurger) purge(ctx context.Context) {
+	before := time.Now().Add(-p.config.TrashRetention)
+
+	limit := p.config.TrashPurgeBatch
+	if limit == 0 {
+		limit = config.DefaultTrashPurgeBatch
+	}
+
+	for ctx.Err() == nil {
+		qctx, cancel := context.WithTimeout(ctx, p.config.BulkTimeout)
+		removed, err := p.repo.Purge(qctx, before, limit)
+		cancel()
+
+		if err != nil {
+			if ctx.Err() == nil {
+				p.log.Error("failed to purge trash: ", err)
+			}
+
+			return
+		}
+
+		if removed > 0 {
+			p.log.Info("purged songs from trash: ", removed)
+		}
+
+		if removed < int64(limit) {
+			return
+		}
+	}
+}
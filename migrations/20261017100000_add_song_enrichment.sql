@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs
+    ADD COLUMN "enrichment_status" TEXT NOT NULL DEFAULT 'enriched'
+        CHECK ("enrichment_status" IN ('pending', 'enriched', 'failed')),
+    ALTER COLUMN "release_date" DROP NOT NULL,
+    ALTER COLUMN "song_text" SET DEFAULT '',
+    ALTER COLUMN "link" SET DEFAULT '';
+
+CREATE TABLE IF NOT EXISTS enrichment_jobs (
+    "id" SERIAL PRIMARY KEY,
+    "created_at" timestamp NOT NULL DEFAULT NOW(),
+    "song_id" INTEGER NOT NULL UNIQUE REFERENCES songs ("id") ON DELETE CASCADE,
+    "attempts" INTEGER NOT NULL DEFAULT 0,
+    "run_at" timestamp NOT NULL DEFAULT NOW(),
+    "last_error" TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS enrichment_jobs_run_at_idx ON enrichment_jobs ("run_at");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE enrichment_jobs;
+
+UPDATE songs SET "release_date" = "created_at"::date WHERE "release_date" IS NULL;
+
+ALTER TABLE songs
+    DROP COLUMN "enrichment_status",
+    ALTER COLUMN "release_date" SET NOT NULL,
+    ALTER COLUMN "song_text" DROP DEFAULT,
+    ALTER COLUMN "link" DROP DEFAULT;
+-- +goose StatementEnd
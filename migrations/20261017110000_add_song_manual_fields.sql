@@ -0,0 +1,9 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs ADD COLUMN "manual_fields" JSONB NOT NULL DEFAULT '[]';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE songs DROP COLUMN "manual_fields";
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE songs
+    ADD COLUMN "search_english" tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english'::regconfig, "song_name"), 'A') ||
+        setweight(to_tsvector('english'::regconfig, "song_group"), 'B') ||
+        setweight(to_tsvector('english'::regconfig, "song_text"), 'C')
+    ) STORED,
+    ADD COLUMN "search_russian" tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('russian'::regconfig, "song_name"), 'A') ||
+        setweight(to_tsvector('russian'::regconfig, "song_group"), 'B') ||
+        setweight(to_tsvector('russian'::regconfig, "song_text"), 'C')
+    ) STORED,
+    ADD COLUMN "search_simple" tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('simple'::regconfig, "song_name"), 'A') ||
+        setweight(to_tsvector('simple'::regconfig, "song_group"), 'B') ||
+        setweight(to_tsvector('simple'::regconfig, "song_text"), 'C')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS songs_search_english_idx ON songs USING GIN ("search_english");
+CREATE INDEX IF NOT EXISTS songs_search_russian_idx ON songs USING GIN ("search_russian");
+CREATE INDEX IF NOT EXISTS songs_search_simple_idx ON songs USING GIN ("search_simple");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE songs
+    DROP COLUMN "search_english",
+    DROP COLUMN "search_russian",
+    DROP COLUMN "search_simple";
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+CREATE EXTENSION IF NOT EXISTS unaccent;
+
+-- unaccent() is only stable, immutable wrapper allows to use it in indexes
+CREATE OR REPLACE FUNCTION f_unaccent(text) RETURNS text
+    LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT
+    AS $$ SELECT public.unaccent('public.unaccent'::regdictionary, $1) $$;
+
+CREATE INDEX IF NOT EXISTS songs_song_name_trgm_idx ON songs USING GIN (lower(f_unaccent("song_name")) gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS songs_song_group_trgm_idx ON songs USING GIN (lower(f_unaccent("song_group")) gin_trgm_ops);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS songs_song_name_trgm_idx;
+DROP INDEX IF EXISTS songs_song_group_trgm_idx;
+DROP FUNCTION IF EXISTS f_unaccent(text);
+-- +goose StatementEnd
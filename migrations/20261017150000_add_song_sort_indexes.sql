@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Keyset pagination over sortable fields, ID breaks ties
+CREATE INDEX IF NOT EXISTS songs_song_name_id_idx ON songs ("song_name", "id");
+CREATE INDEX IF NOT EXISTS songs_song_group_id_idx ON songs ("song_group", "id");
+CREATE INDEX IF NOT EXISTS songs_release_date_id_idx ON songs (coalesce("release_date", '-infinity'::date), "id");
+CREATE INDEX IF NOT EXISTS songs_created_at_id_idx ON songs ("created_at", "id");
+CREATE INDEX IF NOT EXISTS songs_updated_at_id_idx ON songs ("updated_at", "id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS songs_song_name_id_idx;
+DROP INDEX IF EXISTS songs_song_group_id_idx;
+DROP INDEX IF EXISTS songs_release_date_id_idx;
+DROP INDEX IF EXISTS songs_created_at_id_idx;
+DROP INDEX IF EXISTS songs_updated_at_id_idx;
+-- +goose StatementEnd
@@ -0,0 +1,58 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS artists (
+    "id" SERIAL PRIMARY KEY,
+    "created_at" timestamp NOT NULL DEFAULT NOW(),
+    "updated_at" timestamp NOT NULL DEFAULT NOW(),
+    "name" TEXT NOT NULL,
+    "slug" TEXT NOT NULL UNIQUE
+);
+
+-- Names artist is known by, key is lowercase name with collapsed spaces
+CREATE TABLE IF NOT EXISTS artist_aliases (
+    "alias_key" TEXT PRIMARY KEY,
+    "alias" TEXT NOT NULL,
+    "artist_id" INTEGER NOT NULL REFERENCES artists ("id") ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS artist_aliases_artist_id_idx ON artist_aliases ("artist_id");
+
+-- One artist per group name differing in case and spaces only
+WITH groups AS (
+    SELECT min(regexp_replace(btrim("song_group"), '\s+', ' ', 'g')) AS "name"
+    FROM songs
+    GROUP BY lower(regexp_replace(btrim("song_group"), '\s+', ' ', 'g'))
+), slugs AS (
+    SELECT "name",
+        coalesce(nullif(btrim(regexp_replace(lower("name"), '[^[:alnum:]]+', '-', 'g'), '-'), ''), 'artist') AS "slug"
+    FROM groups
+), numbered AS (
+    SELECT "name", "slug", row_number() OVER (PARTITION BY "slug" ORDER BY "name") AS "n"
+    FROM slugs
+)
+INSERT INTO artists ("name", "slug")
+SELECT "name", CASE WHEN "n" = 1 THEN "slug" ELSE "slug" || '-' || "n" END
+FROM numbered;
+
+INSERT INTO artist_aliases ("alias_key", "alias", "artist_id")
+SELECT lower("name"), "name", "id" FROM artists;
+
+ALTER TABLE songs ADD COLUMN "artist_id" INTEGER REFERENCES artists ("id");
+
+-- Group column keeps canonical artist name
+UPDATE songs SET "artist_id" = a."id", "song_group" = a."name"
+FROM artist_aliases al
+JOIN artists a ON a."id" = al."artist_id"
+WHERE al."alias_key" = lower(regexp_replace(btrim(songs."song_group"), '\s+', ' ', 'g'));
+
+ALTER TABLE songs ALTER COLUMN "artist_id" SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS songs_artist_id_idx ON songs ("artist_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE songs DROP COLUMN "artist_id";
+DROP TABLE artist_aliases;
+DROP TABLE artists;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS albums (
+    "id" SERIAL PRIMARY KEY,
+    "created_at" timestamp NOT NULL DEFAULT NOW(),
+    "updated_at" timestamp NOT NULL DEFAULT NOW(),
+    "title" TEXT NOT NULL,
+    "artist_id" INTEGER NOT NULL REFERENCES artists ("id"),
+    "release_date" DATE,
+    "album_type" TEXT NOT NULL DEFAULT 'lp' CHECK ("album_type" IN ('lp', 'ep', 'single', 'compilation')),
+    "cover_link" VARCHAR(1024) NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS albums_artist_id_idx ON albums ("artist_id");
+
+-- Ordered song listing of album, positions are checked at commit so tracks can be reordered
+CREATE TABLE IF NOT EXISTS album_tracks (
+    "album_id" INTEGER NOT NULL REFERENCES albums ("id") ON DELETE CASCADE,
+    "song_id" INTEGER NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "disc_number" INTEGER NOT NULL DEFAULT 1 CHECK ("disc_number" > 0),
+    "track_number" INTEGER NOT NULL CHECK ("track_number" > 0),
+    PRIMARY KEY ("album_id", "song_id"),
+    UNIQUE ("album_id", "disc_number", "track_number") DEFERRABLE INITIALLY DEFERRED
+);
+
+CREATE INDEX IF NOT EXISTS album_tracks_song_id_idx ON album_tracks ("song_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE album_tracks;
+DROP TABLE albums;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Artists credited on song, primary credit follows songs.artist_id
+CREATE TABLE IF NOT EXISTS song_credits (
+    "song_id" INTEGER NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "artist_id" INTEGER NOT NULL REFERENCES artists ("id"),
+    "role" TEXT NOT NULL CHECK ("role" IN ('primary', 'featuring', 'composer', 'lyricist', 'producer')),
+    PRIMARY KEY ("song_id", "artist_id", "role")
+);
+
+CREATE INDEX IF NOT EXISTS song_credits_artist_id_idx ON song_credits ("artist_id");
+
+INSERT INTO song_credits (song_id, artist_id, role)
+SELECT id, artist_id, 'primary' FROM songs;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE song_credits;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Controlled vocabulary of song genres
+CREATE TABLE IF NOT EXISTS genres (
+    "id" SERIAL PRIMARY KEY,
+    "created_at" timestamp NOT NULL DEFAULT NOW(),
+    "name" TEXT NOT NULL,
+    "slug" TEXT NOT NULL UNIQUE
+);
+
+INSERT INTO genres (name, slug) VALUES
+    ('Alternative', 'alternative'),
+    ('Blues', 'blues'),
+    ('Classical', 'classical'),
+    ('Country', 'country'),
+    ('Electronic', 'electronic'),
+    ('Folk', 'folk'),
+    ('Hip-Hop', 'hip-hop'),
+    ('Indie', 'indie'),
+    ('Jazz', 'jazz'),
+    ('Metal', 'metal'),
+    ('Pop', 'pop'),
+    ('Punk', 'punk'),
+    ('R&B', 'r-b'),
+    ('Reggae', 'reggae'),
+    ('Rock', 'rock'),
+    ('Soul', 'soul');
+
+CREATE TABLE IF NOT EXISTS song_genres (
+    "song_id" INTEGER NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "genre_id" INTEGER NOT NULL REFERENCES genres ("id"),
+    PRIMARY KEY ("song_id", "genre_id")
+);
+
+CREATE INDEX IF NOT EXISTS song_genres_genre_id_idx ON song_genres ("genre_id");
+
+-- Free-form lowercase tags
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS "tags" TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS songs_tags_idx ON songs USING GIN ("tags");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS songs_tags_idx;
+ALTER TABLE songs DROP COLUMN IF EXISTS "tags";
+DROP TABLE song_genres;
+DROP TABLE genres;
+-- +goose StatementEnd
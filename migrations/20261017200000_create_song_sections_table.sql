@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lyrics split into sections on write, lyrics of existing songs are parsed by following migration
+CREATE TABLE IF NOT EXISTS song_sections (
+    "song_id" INTEGER NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "section_index" INTEGER NOT NULL CHECK ("section_index" >= 0),
+    "section_type" TEXT NOT NULL CHECK ("section_type" IN ('verse', 'chorus', 'bridge', 'intro', 'outro')),
+    "label" TEXT NOT NULL,
+    "lines" TEXT[] NOT NULL,
+    "repeat_of" INTEGER,
+    PRIMARY KEY ("song_id", "section_index")
+);
+
+CREATE INDEX IF NOT EXISTS song_sections_label_idx ON song_sections ("song_id", lower("label"));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE song_sections;
+-- +goose StatementEnd
@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Sadere/song-depository/internal/lyrics"
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upParseSongSections, downParseSongSections)
+}
+
+// Normalizes lyrics of existing songs and splits them into sections
+func upParseSongSections(ctx context.Context, tx *sql.Tx) error {
+	type song struct {
+		id   uint64
+		text string
+	}
+
+	var songs []song
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, song_text FROM songs WHERE song_text <> ''")
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s song
+
+		err = rows.Scan(&s.id, &s.text)
+		if err != nil {
+			return err
+		}
+
+		songs = append(songs, s)
+	}
+
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	for _, s := range songs {
+		text := lyrics.Normalize(s.text)
+
+		if text != s.text {
+			_, err = tx.ExecContext(ctx, "UPDATE songs SET song_text = $1 WHERE id = $2", text, s.id)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, section := range lyrics.Parse(text) {
+			_, err = tx.ExecContext(ctx,
+				`INSERT INTO song_sections (song_id, section_index, section_type, label, lines, repeat_of)
+				VALUES ($1, $2, $3, $4, $5, $6)`,
+				s.id, section.Index, section.Type, section.Label, []string(section.Lines), section.RepeatOf,
+			)
+
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func downParseSongSections(ctx context.Context, tx *sql.Tx) error {
+	_, err := tx.ExecContext(ctx, "DELETE FROM song_sections")
+	return err
+}
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Synchronized lyrics imported from LRC, word timings come from enhanced LRC
+CREATE TABLE IF NOT EXISTS song_timed_lines (
+    "song_id" INTEGER NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "line_index" INTEGER NOT NULL CHECK ("line_index" >= 0),
+    "start_ms" BIGINT NOT NULL CHECK ("start_ms" >= 0),
+    "end_ms" BIGINT,
+    "text" TEXT NOT NULL,
+    "words" JSONB NOT NULL DEFAULT '[]',
+    PRIMARY KEY ("song_id", "line_index")
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE song_timed_lines;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+-- ChordPro source of song, it's validated on upload and parsed on render
+CREATE TABLE IF NOT EXISTS song_chord_sheets (
+    "song_id" INTEGER PRIMARY KEY REFERENCES songs ("id") ON DELETE CASCADE,
+    "created_at" timestamp NOT NULL DEFAULT NOW(),
+    "updated_at" timestamp NOT NULL DEFAULT NOW(),
+    "source" TEXT NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE song_chord_sheets;
+-- +goose StatementEnd
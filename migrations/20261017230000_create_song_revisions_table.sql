@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Song fields saved on every change, existing songs start with their current state
+CREATE TABLE IF NOT EXISTS song_revisions (
+    "song_id" INTEGER NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "revision" INTEGER NOT NULL CHECK ("revision" > 0),
+    "song_version" INTEGER NOT NULL,
+    "created_at" timestamp NOT NULL DEFAULT NOW(),
+    "author" TEXT NOT NULL,
+    "reason" TEXT NOT NULL,
+    "snapshot" JSONB NOT NULL,
+    PRIMARY KEY ("song_id", "revision")
+);
+
+INSERT INTO song_revisions ("song_id", "revision", "song_version", "author", "reason", "snapshot")
+SELECT s.id, 1, s.version, 'system', 'initial revision', jsonb_build_object(
+    'song', s.song_name,
+    'group', s.song_group,
+    'credits', (SELECT coalesce(jsonb_agg(jsonb_build_object('artist_id', a.id, 'artist', a.name, 'role', c.role)
+        ORDER BY array_position(ARRAY['primary', 'featuring', 'composer', 'lyricist', 'producer'], c.role), a.name), '[]')
+        FROM song_credits c JOIN artists a ON a.id = c.artist_id WHERE c.song_id = s.id),
+    'genres', (SELECT coalesce(jsonb_agg(g.slug ORDER BY g.name), '[]')
+        FROM song_genres sg JOIN genres g ON g.id = sg.genre_id WHERE sg.song_id = s.id),
+    'tags', to_jsonb(s.tags),
+    'text', s.song_text,
+    'release_date', to_char(s.release_date, 'YYYY-MM-DD'),
+    'link', s.link
+)
+FROM songs s;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE song_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,14 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Deleted songs are kept in trash until they are purged
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS "deleted_at" timestamp;
+
+CREATE INDEX IF NOT EXISTS songs_deleted_at_idx ON songs ("deleted_at") WHERE "deleted_at" IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS songs_deleted_at_idx;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS "deleted_at";
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Song name compared for uniqueness: case, accents and punctuation are ignored
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS "name_key" TEXT
+    GENERATED ALWAYS AS (btrim(regexp_replace(lower(f_unaccent("song_name")), '[^[:alnum:]]+', ' ', 'g'))) STORED;
+
+-- Later copies of existing duplicates are exempt from uniqueness until they are merged into kept song
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS "unique_exempt" BOOLEAN NOT NULL DEFAULT false;
+
+UPDATE songs s SET "unique_exempt" = true
+WHERE s."deleted_at" IS NULL AND EXISTS (
+    SELECT 1 FROM songs d
+    WHERE d."artist_id" = s."artist_id" AND d."name_key" = s."name_key"
+        AND d."deleted_at" IS NULL AND d."id" < s."id"
+);
+
+-- Checked at commit so songs can be renamed and merged within transaction
+ALTER TABLE songs ADD CONSTRAINT songs_artist_name_key_excl
+    EXCLUDE USING btree ("artist_id" WITH =, "name_key" WITH =) WHERE ("deleted_at" IS NULL AND NOT "unique_exempt")
+    DEFERRABLE INITIALLY DEFERRED;
+
+-- Songs merged into other ones
+CREATE TABLE IF NOT EXISTS song_redirects (
+    "song_id" INTEGER PRIMARY KEY,
+    "target_id" INTEGER NOT NULL REFERENCES songs ("id") ON DELETE CASCADE,
+    "created_at" timestamp NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS song_redirects_target_id_idx ON song_redirects ("target_id");
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS song_redirects;
+
+ALTER TABLE songs DROP CONSTRAINT IF EXISTS songs_artist_name_key_excl;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS "unique_exempt";
+
+ALTER TABLE songs DROP COLUMN IF EXISTS "name_key";
+-- +goose StatementEnd
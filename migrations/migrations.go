@@ -4,5 +4,7 @@ import (
 	"embed"
 )
 
-//go:embed "*.sql"
+// Go migrations are embedded too, goose finds them by file name
+//
+//go:embed "*.sql" "*.go"
 var Migrations embed.FS